@@ -9,6 +9,8 @@ import (
 	"order-service/common/util"
 	configApp "order-service/config"
 	"order-service/constants"
+	errConstant "order-service/constants/error"
+	errOrder "order-service/constants/error/order"
 	"order-service/domain/dto"
 	"time"
 
@@ -22,6 +24,8 @@ type FieldClient struct {
 type IFieldClient interface {
 	GetFieldByUUID(context.Context, uuid.UUID) (*FieldData, error)
 	UpdateStatus(*dto.UpdateFieldScheduleStatusRequest) error
+	HoldStatus(*dto.HoldFieldScheduleRequest) error
+	ReleaseStatus(*dto.UpdateFieldScheduleStatusRequest) error
 }
 
 func NewFieldClient(client config.IClientConfig) IFieldClient {
@@ -51,17 +55,29 @@ func (f *FieldClient) GetFieldByUUID(ctx context.Context, uuid uuid.UUID) (*Fiel
 
 	resp, _, errs := request.EndStruct(&response)
 	if len(errs) > 0 {
-		return nil, errs[0]
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("user response: %s", response.Message)
+		return nil, errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("user response: %s", response.Message))
 	}
 
 	return &response.Data, nil
 }
 
 func (f *FieldClient) UpdateStatus(request *dto.UpdateFieldScheduleStatusRequest) error {
+	return f.patchStatus("/api/v1/field/schedule/status", request)
+}
+
+func (f *FieldClient) HoldStatus(request *dto.HoldFieldScheduleRequest) error {
+	return f.patchStatus("/api/v1/field/schedule/status/hold", request)
+}
+
+func (f *FieldClient) ReleaseStatus(request *dto.UpdateFieldScheduleStatusRequest) error {
+	return f.patchStatus("/api/v1/field/schedule/status/release", request)
+}
+
+func (f *FieldClient) patchStatus(path string, request any) error {
 	unixTime := time.Now().Unix()
 	generateAPIKey := fmt.Sprintf("%s:%s:%d",
 		configApp.Config.AppName,
@@ -76,7 +92,7 @@ func (f *FieldClient) UpdateStatus(request *dto.UpdateFieldScheduleStatusRequest
 	}
 
 	resp, bodyResp, errs := f.client.Client().Clone().
-		Patch(fmt.Sprintf("%s/api/v1/field/schedule/status", f.client.BaseURL())).
+		Patch(fmt.Sprintf("%s%s", f.client.BaseURL(), path)).
 		Set(constants.XServiceName, configApp.Config.AppName).
 		Set(constants.XApiKey, apiKey).
 		Set(constants.XRequestAt, fmt.Sprintf("%d", unixTime)).
@@ -84,7 +100,7 @@ func (f *FieldClient) UpdateStatus(request *dto.UpdateFieldScheduleStatusRequest
 		End()
 
 	if len(errs) > 0 {
-		return errs[0]
+		return errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
 	}
 
 	var response FieldResponse
@@ -94,7 +110,10 @@ func (f *FieldClient) UpdateStatus(request *dto.UpdateFieldScheduleStatusRequest
 			return err
 		}
 		fieldError := fmt.Errorf("field response: %s", response.Message)
-		return fieldError
+		if resp.StatusCode == http.StatusConflict {
+			return errOrder.ErrFieldAlreadyBooked.Wrap(fieldError)
+		}
+		return errConstant.UpstreamError(resp.StatusCode, fieldError)
 	}
 
 	err = json.Unmarshal([]byte(bodyResp), &response)
@@ -15,6 +15,7 @@ type FieldResponse struct {
 
 type FieldData struct {
 	UUID         uuid.UUID  `json:"uuid"`
+	FieldID      uuid.UUID  `json:"field_id"`
 	FieldName    string     `json:"field_name"`
 	PricePerHour float64    `json:"price_per_hour"`
 	Date         string     `json:"date"`
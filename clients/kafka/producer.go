@@ -0,0 +1,43 @@
+package clients
+
+import (
+	"order-service/config"
+
+	"github.com/IBM/sarama"
+)
+
+type Producer struct {
+	producer sarama.SyncProducer
+}
+
+type IProducer interface {
+	Publish(topic, key string, value []byte) error
+	Close() error
+}
+
+func NewProducer(brokers []string) (IProducer, error) {
+	producerConfig := sarama.NewConfig()
+	producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+	producerConfig.Producer.Retry.Max = config.Config.Kafka.MaxRetry
+	producerConfig.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, producerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Producer{producer: producer}, nil
+}
+
+func (p *Producer) Publish(topic, key string, value []byte) error {
+	_, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+func (p *Producer) Close() error {
+	return p.producer.Close()
+}
@@ -10,6 +10,7 @@ import (
 	"order-service/common/util"
 	configApp "order-service/config"
 	"order-service/constants"
+	errConstant "order-service/constants/error"
 	"order-service/domain/dto"
 	"time"
 
@@ -23,6 +24,7 @@ type PaymentClient struct {
 type IPaymentClient interface {
 	GetPaymentByUUID(context.Context, uuid.UUID) (*PaymentData, error)
 	CreatePaymentLink(context.Context, *dto.PaymentRequest) (*PaymentData, error)
+	CancelPayment(context.Context, uuid.UUID) error
 }
 
 func NewPaymentClient(client config.IClientConfig) IPaymentClient {
@@ -38,33 +40,30 @@ func (p *PaymentClient) GetPaymentByUUID(ctx context.Context, paymentUUID uuid.U
 	)
 
 	apiKey := util.GenerateSHA256(generateAPIKey)
-	token := ctx.Value(constants.Token).(string)
-	bearerToken := fmt.Sprintf("Bearer %s", token)
 
-	var response PaymentResponse
 	request := p.client.Client().Clone().
-		Set(constants.Authorization, bearerToken).
+		Get(fmt.Sprintf("%s/api/v1/payments/%s", p.client.BaseURL(), paymentUUID)).
 		Set(constants.XServiceName, configApp.Config.AppName).
-		Set(constants.XServiceName, apiKey).
-		Set(constants.XRequestAt, fmt.Sprintf("%d", unixTime)).
-		Get(fmt.Sprintf("%s/api/v1/payments/%s", p.client.BaseURL(), paymentUUID))
+		Set(constants.XApiKey, apiKey).
+		Set(constants.XRequestAt, fmt.Sprintf("%d", unixTime))
+
+	// backfill dari command line tidak punya token user, cukup pakai api key
+	if token, ok := ctx.Value(constants.Token).(string); ok && token != "" {
+		request = request.Set(constants.Authorization, fmt.Sprintf("Bearer %s", token))
+	}
 
+	var response PaymentDataResponse
 	resp, _, errrs := request.EndStruct(&response)
 
 	if len(errrs) > 0 {
-		return nil, errrs[0]
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errrs[0])
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("payment response: %s", response.Message)
+		return nil, errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("payment response: %s", response.Message))
 	}
 
-	data, ok := response.Data.(PaymentData)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast response data to PaymentData")
-	}
-
-	return &data, nil
+	return &response.Data, nil
 }
 
 func (p *PaymentClient) CreatePaymentLink(ctx context.Context, req *dto.PaymentRequest) (*PaymentData, error) {
@@ -102,7 +101,7 @@ func (p *PaymentClient) CreatePaymentLink(ctx context.Context, req *dto.PaymentR
 	// Log error jika ada
 	if len(errs) > 0 {
 		log.Printf("❌ Resty Errors: %+v\n", errs)
-		return nil, errs[0]
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
 	}
 
 	// Log status dan response raw
@@ -119,7 +118,7 @@ func (p *PaymentClient) CreatePaymentLink(ctx context.Context, req *dto.PaymentR
 
 	if resp.StatusCode != http.StatusCreated {
 		paymentError := fmt.Errorf("payment response: %s", response.Message)
-		return nil, paymentError
+		return nil, errConstant.UpstreamError(resp.StatusCode, paymentError)
 	}
 
 	// Cek dan cast data
@@ -146,3 +145,40 @@ func (p *PaymentClient) CreatePaymentLink(ctx context.Context, req *dto.PaymentR
 	log.Printf("✅ Final PaymentData parsed: %+v\n", paymentData)
 	return &paymentData, nil
 }
+
+func (p *PaymentClient) CancelPayment(ctx context.Context, paymentUUID uuid.UUID) error {
+	unixTime := time.Now().Unix()
+	generateAPIKey := fmt.Sprintf("%s:%s:%d",
+		configApp.Config.AppName,
+		p.client.SignatureKey(),
+		unixTime,
+	)
+	apiKey := util.GenerateSHA256(generateAPIKey)
+
+	request := p.client.Client().Clone().
+		Post(fmt.Sprintf("%s/api/v1/payments/%s/cancel", p.client.BaseURL(), paymentUUID)).
+		Set(constants.XServiceName, configApp.Config.AppName).
+		Set(constants.XApiKey, apiKey).
+		Set(constants.XRequestAt, fmt.Sprintf("%d", unixTime))
+
+	// background job tidak punya token user, cukup pakai api key
+	if token, ok := ctx.Value(constants.Token).(string); ok && token != "" {
+		request = request.Set(constants.Authorization, fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, bodyResp, errs := request.End()
+	if len(errs) > 0 {
+		return errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var response PaymentResponse
+		err := json.Unmarshal([]byte(bodyResp), &response)
+		if err != nil {
+			return err
+		}
+		return errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("payment response: %s", response.Message))
+	}
+
+	return nil
+}
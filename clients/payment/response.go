@@ -9,6 +9,14 @@ type PaymentResponse struct {
 	Data    interface{} `json:"data"`
 }
 
+// PaymentDataResponse dipakai saat data payment langsung di-decode ke PaymentData.
+type PaymentDataResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Code    int         `json:"code"`
+	Data    PaymentData `json:"data"`
+}
+
 type PaymentData struct {
 	UUID          uuid.UUID `json:"uuid"`
 	OrderID       string    `json:"orderID"`
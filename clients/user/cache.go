@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type userCacheKey struct{}
+
+// userCache menyimpan hasil lookup user selama satu request supaya user yang sama
+// tidak diambil berulang kali dari user service.
+type userCache struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]*UserData
+}
+
+// WithUserCache memasang cache user pada context. Dipanggil sekali per request.
+func WithUserCache(ctx context.Context) context.Context {
+	if userCacheFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, userCacheKey{}, &userCache{users: map[uuid.UUID]*UserData{}})
+}
+
+func userCacheFromContext(ctx context.Context) *userCache {
+	cache, _ := ctx.Value(userCacheKey{}).(*userCache)
+	return cache
+}
+
+func (c *userCache) get(id uuid.UUID) (*UserData, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	user, ok := c.users[id]
+	return user, ok
+}
+
+func (c *userCache) set(user *UserData) {
+	if c == nil || user == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[user.UUID] = user
+}
@@ -9,6 +9,17 @@ type UserResponse struct {
 	Data    UserData `json:"data"`
 }
 
+type UsersResponse struct {
+	Code    int        `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Data    []UserData `json:"data"`
+}
+
+type UsersRequest struct {
+	UUIDs []uuid.UUID `json:"uuids"`
+}
+
 type UserData struct {
 	UUID        uuid.UUID `json:"uuid"`
 	Name        string    `json:"name"`
@@ -9,19 +9,40 @@ import (
 	"order-service/common/util"
 	config2 "order-service/config"
 	"order-service/constants"
+	errConstant "order-service/constants/error"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 )
 
 type UserClient struct {
 	client config.IClientConfig
 }
 
+// batchUnsupportedAt menyimpan waktu (unix nano) user service terakhir menjawab endpoint batch tidak tersedia.
+// Disimpan di level package karena ClientRegistry membuat UserClient baru di setiap pemanggilan.
+var batchUnsupportedAt atomic.Int64
+
+const (
+	defaultLookupConcurrency = 5
+
+	// batchReprobeInterval adalah lama fallback ke lookup satu per satu sebelum endpoint batch dicoba lagi,
+	// supaya 404 sesaat (misalnya saat deploy user service) tidak mematikan batch sampai proses restart.
+	batchReprobeInterval = 5 * time.Minute
+)
+
+func batchDisabled() bool {
+	disabledAt := batchUnsupportedAt.Load()
+	return disabledAt != 0 && time.Since(time.Unix(0, disabledAt)) < batchReprobeInterval
+}
+
 type IUserClient interface {
 	GetUserbyToken(ctx context.Context) (*UserData, error)
 	GetUserbyUUID(context.Context, uuid.UUID) (*UserData, error)
+	GetUsersByUUIDs(context.Context, []uuid.UUID) (map[uuid.UUID]*UserData, error)
 }
 
 func NewUserClient(client config.IClientConfig) IUserClient {
@@ -51,7 +72,7 @@ func (u *UserClient) GetUserbyToken(ctx context.Context) (*UserData, error) {
 	token, ok := val.(string)
 	if !ok || token == "" {
 		logrus.Warn("❌ [GetUserbyToken] TOKEN_NOT_FOUND_IN_CONTEXT or not string")
-		return nil, errors.New("unauthorized")
+		return nil, errConstant.ErrUnauthorized
 	}
 
 	bearerToken := fmt.Sprintf("Bearer %s", token)
@@ -74,7 +95,7 @@ func (u *UserClient) GetUserbyToken(ctx context.Context) (*UserData, error) {
 	// 🔍 Handle response
 	if len(errs) > 0 {
 		logrus.Errorf("❌ [GetUserbyToken] HTTP error: %v", errs[0])
-		return nil, errs[0]
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
 	}
 
 	logrus.Infof("⬅️ [GetUserbyToken] Response status code: %d", resp.StatusCode)
@@ -82,7 +103,7 @@ func (u *UserClient) GetUserbyToken(ctx context.Context) (*UserData, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		logrus.Warnf("🚫 [GetUserbyToken] Unauthorized - user response: %s", response.Message)
-		return nil, fmt.Errorf("user response: %s", response.Message)
+		return nil, errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("user response: %s", response.Message))
 	}
 
 	logrus.Infof("✅ [GetUserbyToken] User data retrieved successfully: %+v", response.Data)
@@ -90,6 +111,11 @@ func (u *UserClient) GetUserbyToken(ctx context.Context) (*UserData, error) {
 }
 
 func (u *UserClient) GetUserbyUUID(ctx context.Context, uuid uuid.UUID) (*UserData, error) {
+	cache := userCacheFromContext(ctx)
+	if user, ok := cache.get(uuid); ok {
+		return user, nil
+	}
+
 	unixTime := time.Now().Unix()
 	generateAPIKey := fmt.Sprintf("%s:%s:%d",
 		config2.Config.AppName,
@@ -110,12 +136,140 @@ func (u *UserClient) GetUserbyUUID(ctx context.Context, uuid uuid.UUID) (*UserDa
 
 	resp, _, errs := request.EndStruct(&response)
 	if len(errs) > 0 {
-		return nil, errs[0]
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("user response: %s", response.Message)
+		return nil, errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("user response: %s", response.Message))
 	}
 
+	cache.set(&response.Data)
 	return &response.Data, nil
 }
+
+// GetUsersByUUIDs mengambil banyak user sekaligus. UUID yang sama hanya diminta sekali,
+// dan jika user service belum punya endpoint batch, lookup dilakukan satu per satu
+// secara paralel dengan jumlah request bersamaan yang dibatasi.
+func (u *UserClient) GetUsersByUUIDs(ctx context.Context, uuids []uuid.UUID) (map[uuid.UUID]*UserData, error) {
+	var (
+		cache   = userCacheFromContext(ctx)
+		users   = make(map[uuid.UUID]*UserData, len(uuids))
+		missing = make([]uuid.UUID, 0, len(uuids))
+	)
+
+	for _, id := range uuids {
+		if _, ok := users[id]; ok {
+			continue
+		}
+
+		if user, ok := cache.get(id); ok {
+			users[id] = user
+			continue
+		}
+
+		users[id] = nil
+		missing = append(missing, id)
+	}
+
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	fetched, err := u.getUsersBatch(ctx, missing)
+	if errors.Is(err, errBatchUnsupported) {
+		fetched, err = u.getUsersConcurrently(ctx, missing)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range fetched {
+		cache.set(user)
+		users[user.UUID] = user
+	}
+
+	for id, user := range users {
+		if user == nil {
+			return nil, errConstant.UpstreamError(http.StatusNotFound, fmt.Errorf("user %s not found", id))
+		}
+	}
+
+	return users, nil
+}
+
+var errBatchUnsupported = errors.New("user batch lookup is not supported")
+
+func (u *UserClient) getUsersBatch(ctx context.Context, uuids []uuid.UUID) ([]*UserData, error) {
+	if batchDisabled() {
+		return nil, errBatchUnsupported
+	}
+
+	unixTime := time.Now().Unix()
+	generateAPIKey := fmt.Sprintf("%s:%s:%d",
+		config2.Config.AppName,
+		u.client.SignatureKey(),
+		unixTime,
+	)
+	apiKey := util.GenerateSHA256(generateAPIKey)
+	token, _ := ctx.Value(constants.Token).(string)
+	bearerToken := fmt.Sprintf("Bearer %s", token)
+
+	var response UsersResponse
+	request := u.client.Client().Clone().
+		Set(constants.Authorization, bearerToken).
+		Set(constants.XServiceName, config2.Config.AppName).
+		Set(constants.XApiKey, apiKey).
+		Set(constants.XRequestAt, fmt.Sprintf("%d", unixTime)).
+		Post(fmt.Sprintf("%s/api/v1/auth/users/batch", u.client.BaseURL())).
+		Send(UsersRequest{UUIDs: uuids})
+
+	resp, _, errs := request.EndStruct(&response)
+	if len(errs) > 0 {
+		return nil, errConstant.ErrUpstreamUnavailable.Wrap(errs[0])
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		logrus.Warnf("user batch lookup not available (status %d), falling back to single lookups", resp.StatusCode)
+		batchUnsupportedAt.Store(time.Now().UnixNano())
+		return nil, errBatchUnsupported
+	default:
+		return nil, errConstant.UpstreamError(resp.StatusCode, fmt.Errorf("user response: %s", response.Message))
+	}
+
+	users := make([]*UserData, 0, len(response.Data))
+	for i := range response.Data {
+		users = append(users, &response.Data[i])
+	}
+
+	return users, nil
+}
+
+func (u *UserClient) getUsersConcurrently(ctx context.Context, uuids []uuid.UUID) ([]*UserData, error) {
+	concurrency := config2.Config.InternalService.User.LookupConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultLookupConcurrency
+	}
+
+	users := make([]*UserData, len(uuids))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, id := range uuids {
+		group.Go(func() error {
+			user, err := u.GetUserbyUUID(groupCtx, id)
+			if err != nil {
+				return err
+			}
+			users[i] = user
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"order-service/clients"
+	"order-service/repositories"
+	"order-service/services"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var backfillBatchSize int
+
+var backfillPaymentLinksCommand = &cobra.Command{
+	Use:   "BackfillPaymentLinks",
+	Short: "Fill payment and invoice links of existing orders from the payment service once and exit",
+	Run: func(cmd *cobra.Command, args []string) {
+		db := bootstrap()
+
+		client := clients.NewClientRegistry()
+		repository := repositories.NewRepositoryRegistry(db)
+		service := services.NewServiceRegistry(repository, client)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		updated, err := service.GetOrder().BackfillPaymentLinks(ctx, backfillBatchSize)
+		if err != nil {
+			logrus.Fatalf("Error backfilling payment links after %d orders: %v", updated, err)
+		}
+
+		logrus.Infof("Backfilled payment links of %d orders", updated)
+	},
+}
+
+func init() {
+	backfillPaymentLinksCommand.Flags().IntVar(&backfillBatchSize, "batch-size", 100, "orders read per batch")
+
+	command.AddCommand(backfillPaymentLinksCommand)
+}
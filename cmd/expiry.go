@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"order-service/clients"
+	"order-service/repositories"
+	"order-service/services"
+	"order-service/workers/expiry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var expireOrdersCommand = &cobra.Command{
+	Use:   "ExpireOrders",
+	Short: "Expire unpaid orders past their payment deadline once and exit",
+	Run: func(cmd *cobra.Command, args []string) {
+		db := bootstrap()
+
+		client := clients.NewClientRegistry()
+		repository := repositories.NewRepositoryRegistry(db)
+		service := services.NewServiceRegistry(repository, client)
+
+		expired, err := expiry.NewSweeper(service).SweepOnce(context.Background())
+		if err != nil {
+			logrus.Fatalf("Error expiring stale orders: %v", err)
+		}
+
+		logrus.Infof("Expired %d stale orders", expired)
+	},
+}
+
+func init() {
+	command.AddCommand(expireOrdersCommand)
+}
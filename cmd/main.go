@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"order-service/clients"
+	clientKafka "order-service/clients/kafka"
+	"order-service/common/lifecycle"
 	"order-service/common/response"
 	"order-service/config"
 	"order-service/constants"
@@ -14,8 +16,13 @@ import (
 	"order-service/domain/models"
 	"order-service/middlewares"
 	"order-service/repositories"
+	orderRepo "order-service/repositories/order"
+	orderTaskRepo "order-service/repositories/ordertask"
 	"order-service/routes"
 	"order-service/services"
+	"order-service/workers/expiry"
+	"order-service/workers/ordertask"
+	"order-service/workers/outbox"
 	"os"
 	"os/signal"
 	"syscall"
@@ -25,49 +32,132 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 var command = &cobra.Command{
-	Use:   "Serve",
-	Short: "Run the HTTP server and Kafka consumer",
-	Run: func(cmd *cobra.Command, args []string) {
-		config.Init()
+	Use:          "Serve",
+	Short:        "Run the HTTP server and Kafka consumer",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db := bootstrap()
 
-		db, err := config.InitDatabase()
+		client := clients.NewClientRegistry()
+		repository := repositories.NewRepositoryRegistry(db)
+		service := services.NewServiceRegistry(repository, client)
+		controller := controllers.NewControllerRegistry(service)
+
+		shutdownTimeout := config.Config.ShutdownTimeout()
+		manager := lifecycle.NewManager(shutdownTimeout)
+		manager.OnClose("database", func() error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		})
+
+		producer, err := clientKafka.NewProducer(config.Config.Kafka.Brokers)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("create kafka producer: %w", err)
 		}
+		manager.OnClose("kafka producer", producer.Close)
 
-		loc, err := time.LoadLocation("Asia/Jakarta")
+		consumerGroup, err := newKafkaConsumer(service, producer)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("create kafka consumer group: %w", err)
 		}
 
-		time.Local = loc
+		relay := outbox.NewRelay(repository, producer)
+		sweeper := expiry.NewSweeper(service)
+		retrier := ordertask.NewRetrier(service)
+
+		manager.Add(lifecycle.Func("outbox relay", func(ctx context.Context) error {
+			relay.Run(ctx)
+			return nil
+		}))
+		manager.Add(lifecycle.Func("expiry sweeper", func(ctx context.Context) error {
+			sweeper.Run(ctx)
+			return nil
+		}))
+		manager.Add(lifecycle.Func("order task retrier", func(ctx context.Context) error {
+			retrier.Run(ctx)
+			return nil
+		}))
+		manager.Add(consumerGroup)
+		manager.Add(lifecycle.NewHTTPServer(&http.Server{
+			Addr:    fmt.Sprintf(":%d", config.Config.Port),
+			Handler: newRouter(controller, client),
+		}, shutdownTimeout))
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err = manager.Run(ctx)
+		if err != nil {
+			return err
+		}
 
-		err = db.AutoMigrate(
-			&models.Order{},
-			&models.OrderHistory{},
-			&models.OrderField{},
-		)
+		logrus.Info("Order service stopped gracefully")
+		return nil
+	},
+}
 
-		client := clients.NewClientRegistry()
-		repository := repositories.NewRepositoryRegistry(db)
-		service := services.NewServiceRegistry(repository, client)
-		controller := controllers.NewControllerRegistry(service)
+func bootstrap() *gorm.DB {
+	config.Init()
 
-		serveHttp(controller, client)
-		serveKafkaConsumer(service)
-	},
+	db, err := config.InitDatabase()
+	if err != nil {
+		panic(err)
+	}
+
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		panic(err)
+	}
+
+	time.Local = loc
+
+	// kode duplikat dari generator lama harus dibereskan dulu sebelum unique index dibuat
+	err = orderRepo.DedupeCodes(context.Background(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	err = orderTaskRepo.MigrateLegacyTasks(context.Background(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.AutoMigrate(
+		&models.Order{},
+		&models.OrderHistory{},
+		&models.OrderField{},
+		&models.ProcessedEvent{},
+		&models.OrderOutbox{},
+		&models.OrderSagaStep{},
+		&models.OrderTask{},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = orderRepo.SeedCodeSequence(context.Background(), db, config.Config.Order.CodeResetDaily)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
 }
 
 func Run() {
 	if err := command.Execute(); err != nil {
-		panic(err)
+		logrus.Errorf("order service exited with error: %v", err)
+		os.Exit(1)
 	}
 }
 
-func serveHttp(controllers controllers.IControllerRegistry, client clients.IClientRegistry) {
+func newRouter(controllers controllers.IControllerRegistry, client clients.IClientRegistry) *gin.Engine {
 	router := gin.Default()
 	router.Use(middlewares.HandlePanic())
 	router.Use(gin.Logger())
@@ -110,21 +200,14 @@ func serveHttp(controllers controllers.IControllerRegistry, client clients.IClie
 	route := routes.NewRouteRegistry(group, controllers, client)
 	route.Serve()
 
-	go func() {
-		port := fmt.Sprintf(":%d", config.Config.Port)
-		router.Run(port)
-	}()
+	return router
 }
 
-func serveKafkaConsumer(service services.IServiceRegistry) {
-	kafkaConsumerConfig := sarama.NewConfig()
-	kafkaConsumerConfig.Consumer.MaxWaitTime = time.Duration(config.Config.Kafka.MaxWaitTimeInMs) * time.Millisecond
-	kafkaConsumerConfig.Consumer.MaxProcessingTime = time.Duration(config.Config.Kafka.MaxProcessingTimeInMs) * time.Millisecond
-	kafkaConsumerConfig.Consumer.Retry.Backoff = time.Duration(config.Config.Kafka.BackoffTimeInMs) * time.Millisecond
-	kafkaConsumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
-	kafkaConsumerConfig.Consumer.Offsets.AutoCommit.Enable = true
-	kafkaConsumerConfig.Consumer.Offsets.AutoCommit.Interval = 1 * time.Second
-	kafkaConsumerConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+func newKafkaConsumer(service services.IServiceRegistry, producer clientKafka.IProducer) (lifecycle.Component, error) {
+	kafkaConsumerConfig, err := kafka.NewSaramaConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	brokers := config.Config.Kafka.Brokers
 	groupID := config.Config.Kafka.GroupID
@@ -132,38 +215,14 @@ func serveKafkaConsumer(service services.IServiceRegistry) {
 
 	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupID, kafkaConsumerConfig)
 	if err != nil {
-		logrus.Fatalf("Error creating Kafka consumer group: %v", err)
-		return
+		return nil, err
 	}
 
-	defer consumerGroup.Close()
-
-	consumer := kafka.NewConsumerGroup()
+	consumer := kafka.NewConsumerGroup(producer)
 	kafkaRegistry := kafka2.NewKafkaRegistry(service)
 	kafkaConsumer := kafka.NewKafkaConsumer(consumer, kafkaRegistry)
 	kafkaConsumer.Register()
 
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
-	ctx, cancle := context.WithCancel(context.Background())
-	defer cancle()
-
-	go func() {
-		for {
-			err = consumerGroup.Consume(ctx, topic, consumer)
-			if err != nil {
-				logrus.Errorf("Error consuming Kafka messages: %v", err)
-				panic(err)
-			}
-
-			if ctx.Err() != nil {
-				return
-			}
-		}
-	}()
-
-	logrus.Infof("Kafka consumer up and running! Listening to topics: %v", topic)
-
-	<-signals
-	logrus.Info("Termination signal received. Shutting down Kafka consumer...")
+	logrus.Infof("Kafka consumer ready, listening to topics: %v", topic)
+	return lifecycle.NewConsumerGroup(consumerGroup, topic, consumer, config.Config.ShutdownTimeout()), nil
 }
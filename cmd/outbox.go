@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	clientKafka "order-service/clients/kafka"
+	"order-service/config"
+	"order-service/repositories"
+	"order-service/workers/outbox"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var outboxRelayCommand = &cobra.Command{
+	Use:   "OutboxRelay",
+	Short: "Publish pending order events from the outbox to Kafka",
+	Run: func(cmd *cobra.Command, args []string) {
+		db := bootstrap()
+		repository := repositories.NewRepositoryRegistry(db)
+
+		producer, err := clientKafka.NewProducer(config.Config.Kafka.Brokers)
+		if err != nil {
+			logrus.Fatalf("Error creating Kafka producer: %v", err)
+		}
+		defer producer.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		outbox.NewRelay(repository, producer).Run(ctx)
+	},
+}
+
+func init() {
+	command.AddCommand(outboxRelayCommand)
+}
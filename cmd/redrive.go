@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/clients"
+	"order-service/config"
+	"order-service/repositories"
+	"order-service/services"
+	"order-service/workers/redrive"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var redriveFlags struct {
+	topic    string
+	file     string
+	orderIDs []string
+	from     string
+	to       string
+	dryRun   bool
+}
+
+var redriveCommand = &cobra.Command{
+	Use:          "Redrive",
+	Short:        "Reprocess dead-lettered payment events from a DLQ topic or a JSON-lines file",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (redriveFlags.topic == "") == (redriveFlags.file == "") {
+			return errors.New("exactly one of --topic or --file is required")
+		}
+
+		filter, err := redriveFilter()
+		if err != nil {
+			return err
+		}
+
+		db := bootstrap()
+
+		client := clients.NewClientRegistry()
+		repository := repositories.NewRepositoryRegistry(db)
+		service := services.NewServiceRegistry(repository, client)
+
+		var source redrive.ISource
+		if redriveFlags.file != "" {
+			source = redrive.NewFileSource(redriveFlags.file)
+		} else {
+			source = redrive.NewTopicSource(config.Config.Kafka.Brokers, redriveFlags.topic)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		report, err := redrive.NewRedriver(service, filter, redriveFlags.dryRun).Run(ctx, source)
+		logrus.Infof("Redrive finished (dry-run: %t): read %d, skipped %d, unchanged %d, changed %d, failed %d",
+			redriveFlags.dryRun, report.Read, report.Skipped, report.Unchanged, report.Changed, report.Failed)
+		if err != nil {
+			return err
+		}
+
+		if report.Failed > 0 {
+			return fmt.Errorf("%d payment events failed to redrive", report.Failed)
+		}
+
+		return nil
+	},
+}
+
+func redriveFilter() (redrive.Filter, error) {
+	var filter redrive.Filter
+
+	for _, orderID := range redriveFlags.orderIDs {
+		id, err := uuid.Parse(orderID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --order-id %q: %w", orderID, err)
+		}
+		filter.OrderIDs = append(filter.OrderIDs, id)
+	}
+
+	if redriveFlags.from != "" {
+		from, err := time.Parse(time.RFC3339, redriveFlags.from)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --from: %w", err)
+		}
+		filter.From = &from
+	}
+
+	if redriveFlags.to != "" {
+		to, err := time.Parse(time.RFC3339, redriveFlags.to)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --to: %w", err)
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+func init() {
+	redriveCommand.Flags().StringVar(&redriveFlags.topic, "topic", "", "DLQ topic to read, e.g. payment-service-callback.dlq")
+	redriveCommand.Flags().StringVar(&redriveFlags.file, "file", "", "JSON-lines file of payment messages")
+	redriveCommand.Flags().StringSliceVar(&redriveFlags.orderIDs, "order-id", nil, "only redrive events for these order UUIDs")
+	redriveCommand.Flags().StringVar(&redriveFlags.from, "from", "", "only redrive events sent at or after this RFC3339 time")
+	redriveCommand.Flags().StringVar(&redriveFlags.to, "to", "", "only redrive events sent at or before this RFC3339 time")
+	redriveCommand.Flags().BoolVar(&redriveFlags.dryRun, "dry-run", false, "report which orders would change without applying anything")
+
+	command.AddCommand(redriveCommand)
+}
@@ -13,7 +13,19 @@ type ValidationResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
-var ErrValidator = map[string]string{}
+var ErrValidator = map[string]string{
+	"oneof":           "%s must be one of [%s]",
+	"uuid":            "%s must be a valid UUID",
+	"unique":          "%s must not contain duplicate values",
+	"min":             "%s must be at least %s",
+	"max":             "%s must not exceed %s",
+	"gte":             "%s must be greater than or equal to %s",
+	"required_unless": "%s is required unless %s",
+	"excluded_if":     "%s is not supported when %s",
+	"sortcolumn":      "%s contains an unsupported sort column",
+	"sortorder":       "%s must be asc or desc",
+	"orderexpand":     "%s only supports fields and history",
+}
 
 func ErrValidationResponse(err error) (validationResponse []ValidationResponse) {
 	var fieldErrors validator.ValidationErrors
@@ -62,7 +74,47 @@ func ErrValidationResponse(err error) (validationResponse []ValidationResponse)
 	return validationResponse
 }
 
+// AppError adalah error domain yang membawa kode error, HTTP status dan pesan untuk client.
+type AppError struct {
+	Code       string
+	HTTPStatus int
+	Message    string
+	Err        error
+}
+
+func New(code string, httpStatus int, message string) *AppError {
+	return &AppError{
+		Code:       code,
+		HTTPStatus: httpStatus,
+		Message:    message,
+	}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is membuat errors.Is tetap cocok dengan error sentinel walaupun sudah di-Wrap.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// Wrap mengembalikan salinan error dengan cause, error sentinel-nya tidak berubah.
+func (e *AppError) Wrap(cause error) *AppError {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
 func WrapError(err error) error {
-	logrus.Error("error: %v", err)
+	logrus.Errorf("error: %v", err)
 	return err
 }
@@ -0,0 +1,48 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+type httpServer struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+}
+
+// NewHTTPServer menjalankan server sampai ctx dibatalkan, lalu menunggu request yang
+// sedang berjalan selesai paling lama shutdownTimeout.
+func NewHTTPServer(server *http.Server, shutdownTimeout time.Duration) Component {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &httpServer{server: server, shutdownTimeout: shutdownTimeout}
+}
+
+func (h *httpServer) Name() string {
+	return "http server " + h.server.Addr
+}
+
+func (h *httpServer) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), h.shutdownTimeout)
+	defer cancel()
+
+	return h.server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,85 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+// Drainer diimplementasikan handler yang bisa berhenti mengambil message baru
+// dan menunggu message yang sedang diproses selesai.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+type consumerGroup struct {
+	group        sarama.ConsumerGroup
+	topics       []string
+	handler      sarama.ConsumerGroupHandler
+	drainTimeout time.Duration
+}
+
+// NewConsumerGroup menjalankan consumer group sampai ctx dibatalkan. Jika handler adalah Drainer,
+// message yang sedang diproses diselesaikan dulu, lalu group ditutup.
+func NewConsumerGroup(group sarama.ConsumerGroup, topics []string, handler sarama.ConsumerGroupHandler, drainTimeout time.Duration) Component {
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultShutdownTimeout
+	}
+
+	return &consumerGroup{group: group, topics: topics, handler: handler, drainTimeout: drainTimeout}
+}
+
+func (c *consumerGroup) Name() string {
+	return "kafka consumer " + strings.Join(c.topics, ",")
+}
+
+func (c *consumerGroup) Run(ctx context.Context) (err error) {
+	defer func() {
+		err = errors.Join(err, c.group.Close())
+	}()
+
+	// session tidak ikut dibatalkan oleh ctx, supaya handler yang sedang berjalan sempat selesai
+	consumeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.consume(consumeCtx)
+	}()
+
+	select {
+	case err = <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	if drainer, ok := c.handler.(Drainer); ok {
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), c.drainTimeout)
+		defer drainCancel()
+
+		drainErr := drainer.Drain(drainCtx)
+		if drainErr != nil {
+			logrus.Warnf("[Lifecycle] %s did not drain in time: %v", c.Name(), drainErr)
+		}
+	}
+
+	cancel()
+	return <-errCh
+}
+
+func (c *consumerGroup) consume(ctx context.Context) error {
+	for {
+		err := c.group.Consume(ctx, c.topics, c.handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) || ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Component adalah bagian aplikasi yang berjalan sampai context dibatalkan.
+// Run harus menyelesaikan pekerjaan yang sedang berjalan sebelum kembali.
+type Component interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type closer struct {
+	name string
+	fn   func() error
+}
+
+type Manager struct {
+	components      []Component
+	closers         []closer
+	shutdownTimeout time.Duration
+}
+
+type IManager interface {
+	Add(Component)
+	OnClose(string, func() error)
+	Run(context.Context) error
+}
+
+func NewManager(shutdownTimeout time.Duration) IManager {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// OnClose mendaftarkan resource yang ditutup setelah semua component berhenti,
+// dengan urutan kebalikan dari pendaftaran.
+func (m *Manager) OnClose(name string, fn func() error) {
+	m.closers = append(m.closers, closer{name: name, fn: fn})
+}
+
+// Run menjalankan semua component sampai ctx dibatalkan atau salah satu component gagal,
+// lalu menghentikan semuanya dalam batas shutdownTimeout dan menutup resource.
+func (m *Manager) Run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		errCh  = make(chan error, len(m.components))
+		runErr error
+	)
+
+	for _, component := range m.components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logrus.Infof("[Lifecycle] starting %s", component.Name())
+			err := component.Run(runCtx)
+			if err != nil {
+				errCh <- fmt.Errorf("%s: %w", component.Name(), err)
+				return
+			}
+			logrus.Infof("[Lifecycle] %s stopped", component.Name())
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Info("[Lifecycle] termination signal received, shutting down")
+	case runErr = <-errCh:
+		logrus.Errorf("[Lifecycle] component failed, shutting down: %v", runErr)
+	}
+
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(m.shutdownTimeout):
+		runErr = errors.Join(runErr, fmt.Errorf("components did not stop within %s", m.shutdownTimeout))
+	}
+
+	// component yang belum berhenti masih bisa mengirim error, jadi channel tidak ditutup
+drain:
+	for {
+		select {
+		case err := <-errCh:
+			runErr = errors.Join(runErr, err)
+		default:
+			break drain
+		}
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		err := m.closers[i].fn()
+		if err != nil {
+			logrus.Errorf("[Lifecycle] error closing %s: %v", m.closers[i].name, err)
+			runErr = errors.Join(runErr, fmt.Errorf("close %s: %w", m.closers[i].name, err))
+		}
+	}
+
+	return runErr
+}
+
+type funcComponent struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Func membungkus worker sederhana yang berhenti sendiri ketika ctx dibatalkan.
+func Func(name string, run func(ctx context.Context) error) Component {
+	return &funcComponent{name: name, run: run}
+}
+
+func (f *funcComponent) Name() string {
+	return f.name
+}
+
+func (f *funcComponent) Run(ctx context.Context) error {
+	return f.run(ctx)
+}
@@ -1,17 +1,21 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 	"order-service/constants"
 	errConstant "order-service/constants/error"
 
+	errWrap "order-service/common/error"
+
 	"github.com/gin-gonic/gin"
 )
 
 type Response struct {
-	Status  string      `json:"status"`
-	Message any         `json:"message"`
-	Data    interface{} `json:"data"`
+	Status    string      `json:"status"`
+	Message   any         `json:"message"`
+	ErrorCode string      `json:"errorCode,omitempty"`
+	Data      interface{} `json:"data"`
 	//Token   *string     `json:"token,omitempty"`
 }
 
@@ -35,19 +39,27 @@ func HttpResponse(param ParamHTTPResp) {
 		return
 	}
 
-	message := errConstant.ErrInternalServerError.Error()
+	// status dan errorCode diambil dari AppError, error lain dianggap internal server error
+	code := errConstant.ErrInternalServerError.HTTPStatus
+	errorCode := errConstant.ErrInternalServerError.Code
+	message := errConstant.ErrInternalServerError.Message
+
+	var appErr *errWrap.AppError
+	if errors.As(param.Err, &appErr) {
+		code = appErr.HTTPStatus
+		errorCode = appErr.Code
+		message = appErr.Message
+	}
+
 	if param.Message != nil {
 		message = *param.Message
-	} else if param.Err != nil {
-		if errConstant.ErrMapping(param.Err) {
-			message = param.Err.Error()
-		}
 	}
 
-	param.Gin.JSON(param.Code, Response{
-		Status:  constants.Error,
-		Message: message,
-		Data:    param.Data,
+	param.Gin.JSON(code, Response{
+		Status:    constants.Error,
+		Message:   message,
+		ErrorCode: errorCode,
+		Data:      param.Data,
 	})
 
 	return
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Cursor adalah posisi keyset (created_at, id) dari baris terakhir yang sudah dibaca.
+// Client menerimanya dalam bentuk string base64 yang tidak perlu dipahami isinya.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+}
+
+type CursorPaginationParams struct {
+	Limit     int
+	Prev      bool
+	HasCursor bool
+	HasMore   bool
+	First     *Cursor
+	Last      *Cursor
+	Data      interface{}
+}
+
+type CursorPaginationResult struct {
+	NextCursor *string     `json:"nextCursor"`
+	PrevCursor *string     `json:"prevCursor"`
+	Limit      int         `json:"limit"`
+	Data       interface{} `json:"data"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func GenerateCursorPagination(params CursorPaginationParams) CursorPaginationResult {
+	var hasNext, hasPrev bool
+	if params.Prev {
+		hasNext = params.HasCursor
+		hasPrev = params.HasMore
+	} else {
+		hasNext = params.HasMore
+		hasPrev = params.HasCursor
+	}
+
+	result := CursorPaginationResult{
+		Limit: params.Limit,
+		Data:  params.Data,
+	}
+
+	if hasNext && params.Last != nil {
+		next := EncodeCursor(*params.Last)
+		result.NextCursor = &next
+	}
+
+	if hasPrev && params.First != nil {
+		prev := EncodeCursor(*params.First)
+		result.PrevCursor = &prev
+	}
+
+	return result
+}
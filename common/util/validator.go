@@ -0,0 +1,54 @@
+package util
+
+import (
+	"order-service/constants"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator membuat validator dengan custom tag yang dipakai di service ini.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	_ = validate.RegisterValidation("sortcolumn", validateSortColumn)
+	_ = validate.RegisterValidation("sortorder", validateSortOrder)
+	_ = validate.RegisterValidation("orderexpand", validateOrderExpand)
+	return validate
+}
+
+func validateSortColumn(fl validator.FieldLevel) bool {
+	columns := constants.SplitSortValues(fl.Field().String())
+	if len(columns) == 0 {
+		return false
+	}
+
+	for _, column := range columns {
+		if _, ok := constants.OrderSortColumns[column]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func validateSortOrder(fl validator.FieldLevel) bool {
+	orders := constants.SplitSortValues(fl.Field().String())
+	if len(orders) == 0 {
+		return false
+	}
+
+	for _, order := range orders {
+		if !constants.SortOrder(strings.ToLower(order)).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+func validateOrderExpand(fl validator.FieldLevel) bool {
+	for _, expand := range constants.SplitSortValues(fl.Field().String()) {
+		if !constants.OrderExpands[constants.OrderExpand(expand)] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"order-service/common/util"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -19,6 +20,16 @@ type AppConfig struct {
 	RateLimiterTimeSecond int             `json:"rateLimiterTimeSecond"`
 	InternalService       InternalService `json:"internalService"`
 	Kafka                 Kafka           `json:"kafka"`
+	Order                 Order           `json:"order"`
+	ShutdownTimeoutInSec  int             `json:"shutdownTimeoutInSec"`
+}
+
+// ShutdownTimeout adalah batas waktu menunggu request dan message yang sedang diproses saat shutdown.
+func (c AppConfig) ShutdownTimeout() time.Duration {
+	if c.ShutdownTimeoutInSec <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.ShutdownTimeoutInSec) * time.Second
 }
 
 type Database struct {
@@ -40,8 +51,9 @@ type InternalService struct {
 }
 
 type User struct {
-	Host         string `json:"host"`
-	SignatureKey string `json:"signatureKey"`
+	Host              string `json:"host"`
+	SignatureKey      string `json:"signatureKey"`
+	LookupConcurrency int    `json:"lookupConcurrency"`
 }
 
 type Field struct {
@@ -55,14 +67,60 @@ type Payment struct {
 }
 
 type Kafka struct {
-	Brokers               []string `json:"brokers"`
-	TimeoutInMs           int      `json:"timeoutInMs"`
-	MaxRetry              int      `json:"maxRetry"`
-	MaxWaitTimeInMs       int      `json:"maxWaitTimeInMs"`
-	MaxProcessingTimeInMs int      `json:"maxProcessingTimeInMs"`
-	BackoffTimeInMs       int      `json:"backoffTimeInMs"`
-	Topics                []string `json:"topics"`
-	GroupID               string   `json:"groupID"`
+	Brokers                []string `json:"brokers"`
+	TimeoutInMs            int      `json:"timeoutInMs"`
+	MaxRetry               int      `json:"maxRetry"`
+	MaxWaitTimeInMs        int      `json:"maxWaitTimeInMs"`
+	MaxProcessingTimeInMs  int      `json:"maxProcessingTimeInMs"`
+	BackoffTimeInMs        int      `json:"backoffTimeInMs"`
+	Topics                 []string `json:"topics"`
+	GroupID                string   `json:"groupID"`
+	OrderEventTopic        string   `json:"orderEventTopic"`
+	OutboxIntervalInMs     int      `json:"outboxIntervalInMs"`
+	OutboxBatchSize        int      `json:"outboxBatchSize"`
+	OutboxMaxAttempts      int      `json:"outboxMaxAttempts"`
+	RetryBackoffInMs       int      `json:"retryBackoffInMs"`
+	RetryMaxBackoffInMs    int      `json:"retryMaxBackoffInMs"`
+	DeadLetterSuffix       string   `json:"deadLetterSuffix"`
+	OffsetInitial          string   `json:"offsetInitial"`
+	RebalanceStrategy      string   `json:"rebalanceStrategy"`
+	CommitMode             string   `json:"commitMode"`
+	AutoCommitIntervalInMs int      `json:"autoCommitIntervalInMs"`
+}
+
+type Order struct {
+	CodeFormat               string `json:"codeFormat"`
+	CodeResetDaily           bool   `json:"codeResetDaily"`
+	PaymentExpiryInMinutes   int    `json:"paymentExpiryInMinutes"`
+	ExpirySweepIntervalInSec int    `json:"expirySweepIntervalInSec"`
+	ExpirySweepBatchSize     int    `json:"expirySweepBatchSize"`
+	ExpirySweepGraceInSec    int    `json:"expirySweepGraceInSec"`
+	FieldSyncIntervalInSec   int    `json:"fieldSyncIntervalInSec"` // worker order_tasks, nama lama dipertahankan
+	FieldSyncBatchSize       int    `json:"fieldSyncBatchSize"`
+	SagaRecoveryGraceInSec   int    `json:"sagaRecoveryGraceInSec"`
+}
+
+func (o Order) PaymentExpiry() time.Duration {
+	if o.PaymentExpiryInMinutes <= 0 {
+		return 1 * time.Hour
+	}
+	return time.Duration(o.PaymentExpiryInMinutes) * time.Minute
+}
+
+// ExpirySweepGrace memberi waktu callback settlement yang terlambat sampai sebelum sweeper meng-expire order.
+func (o Order) ExpirySweepGrace() time.Duration {
+	if o.ExpirySweepGraceInSec <= 0 {
+		return 2 * time.Minute
+	}
+	return time.Duration(o.ExpirySweepGraceInSec) * time.Second
+}
+
+// SagaRecoveryGrace adalah lama order boleh tertahan di tengah saga Create sebelum dianggap gagal.
+func (o Order) SagaRecoveryGrace() time.Duration {
+	if o.SagaRecoveryGraceInSec <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(o.SagaRecoveryGraceInSec) * time.Second
 }
 
 func Init() {
@@ -1,25 +1,32 @@
 package error
 
-import "errors"
+import (
+	"net/http"
+
+	errWrap "order-service/common/error"
+)
 
 var (
-	ErrInternalServerError = errors.New("internal server error")
-	ErrSQLError            = errors.New("database server failed to execute the query")
-	ErrToManyRequests      = errors.New("too many requests")
-	ErrUnauthorized        = errors.New("unauthorized")
-	ErrInvalidToken        = errors.New("invalid token")
-	ErrForbidden           = errors.New("forbidden")
-	ErrInvalidUploadFile   = errors.New("invalid upload file")
-	ErrSizeTooLarge        = errors.New("size too large")
+	ErrInternalServerError = errWrap.New("INTERNAL_SERVER_ERROR", http.StatusInternalServerError, "internal server error")
+	ErrSQLError            = errWrap.New("SQL_ERROR", http.StatusInternalServerError, "database server failed to execute the query")
+	ErrToManyRequests      = errWrap.New("TOO_MANY_REQUESTS", http.StatusTooManyRequests, "too many requests")
+	ErrUnauthorized        = errWrap.New("UNAUTHORIZED", http.StatusUnauthorized, "unauthorized")
+	ErrInvalidToken        = errWrap.New("INVALID_TOKEN", http.StatusUnauthorized, "invalid token")
+	ErrForbidden           = errWrap.New("FORBIDDEN", http.StatusForbidden, "forbidden")
+	ErrInvalidUploadFile   = errWrap.New("INVALID_UPLOAD_FILE", http.StatusBadRequest, "invalid upload file")
+	ErrSizeTooLarge        = errWrap.New("SIZE_TOO_LARGE", http.StatusRequestEntityTooLarge, "size too large")
+	ErrBadRequest          = errWrap.New("BAD_REQUEST", http.StatusBadRequest, "bad request")
+	ErrValidation          = errWrap.New("VALIDATION_ERROR", http.StatusUnprocessableEntity, http.StatusText(http.StatusUnprocessableEntity))
+	ErrUpstreamUnavailable = errWrap.New("UPSTREAM_UNAVAILABLE", http.StatusServiceUnavailable, "upstream service unavailable")
+	ErrUpstreamError       = errWrap.New("UPSTREAM_ERROR", http.StatusBadGateway, "upstream service returned an error")
+	ErrInvalidMessage      = errWrap.New("INVALID_MESSAGE", http.StatusUnprocessableEntity, "message does not match its schema")
+	ErrUnprocessable       = errWrap.New("UNPROCESSABLE_MESSAGE", http.StatusUnprocessableEntity, "message can not be applied and needs manual action")
 )
 
-var GeneralErrrors = []error{
-	ErrInternalServerError,
-	ErrSQLError,
-	ErrToManyRequests,
-	ErrUnauthorized,
-	ErrInvalidToken,
-	ErrForbidden,
-	ErrInvalidUploadFile,
-	ErrSizeTooLarge,
+// UpstreamError membedakan service lain yang sedang mati (5xx) dengan request yang ditolak (4xx).
+func UpstreamError(statusCode int, cause error) error {
+	if statusCode >= http.StatusInternalServerError {
+		return ErrUpstreamUnavailable.Wrap(cause)
+	}
+	return ErrUpstreamError.Wrap(cause)
 }
@@ -1,13 +1,22 @@
 package error
 
-import "errors"
+import (
+	"net/http"
 
-var (
-	ErrOrderNotFound      = errors.New("order not found")
-	ErrFieldAlreadyBooked = errors.New("field schedule already booked")
+	errWrap "order-service/common/error"
 )
 
-var OrderError = []error{
-	ErrOrderNotFound,
-	ErrFieldAlreadyBooked,
-}
+var (
+	ErrOrderNotFound        = errWrap.New("ORDER_NOT_FOUND", http.StatusNotFound, "order not found")
+	ErrFieldAlreadyBooked   = errWrap.New("FIELD_ALREADY_BOOKED", http.StatusConflict, "field schedule already booked")
+	ErrInvalidFieldPrice    = errWrap.New("INVALID_FIELD_PRICE", http.StatusUnprocessableEntity, "field schedule has an invalid price")
+	ErrInvalidFieldName     = errWrap.New("INVALID_FIELD_NAME", http.StatusUnprocessableEntity, "field schedule has no field name")
+	ErrPhoneNumberMissing   = errWrap.New("PHONE_NUMBER_REQUIRED", http.StatusUnprocessableEntity, "user phone number is required")
+	ErrInvalidFieldSchedule = errWrap.New("INVALID_FIELD_SCHEDULE", http.StatusUnprocessableEntity, "field schedule id is invalid or duplicated")
+
+	ErrInvalidStatusTransition = errWrap.New("INVALID_STATUS_TRANSITION", http.StatusConflict, "invalid order status transition")
+	ErrUnknownPaymentStatus    = errWrap.New("UNKNOWN_PAYMENT_STATUS", http.StatusUnprocessableEntity, "unknown payment status")
+	ErrPaymentOnClosedOrder    = errWrap.New("PAYMENT_ON_CLOSED_ORDER", http.StatusConflict, "payment settled for an order that is already closed")
+
+	ErrInvalidCursor = errWrap.New("INVALID_CURSOR", http.StatusBadRequest, "invalid pagination cursor")
+)
@@ -0,0 +1,19 @@
+package error
+
+import (
+	"fmt"
+	"order-service/constants"
+)
+
+type StatusTransitionError struct {
+	From constants.OrderStatusString
+	To   constants.OrderStatusString
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s from %s to %s", ErrInvalidStatusTransition.Error(), e.From, e.To)
+}
+
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
@@ -0,0 +1,29 @@
+package constants
+
+type OrderEventName string
+
+const (
+	OrderCreatedEvent   OrderEventName = "order.created"
+	OrderPaidEvent      OrderEventName = "order.paid"
+	OrderExpiredEvent   OrderEventName = "order.expired"
+	OrderCancelledEvent OrderEventName = "order.cancelled"
+	OrderFailedEvent    OrderEventName = "order.failed"
+)
+
+var mapStatusToOrderEvent = map[OrderStatus]OrderEventName{
+	Pending:        OrderCreatedEvent,
+	PaymentSuccess: OrderPaidEvent,
+	Expired:        OrderExpiredEvent,
+	Cancelled:      OrderCancelledEvent,
+	Failed:         OrderFailedEvent,
+}
+
+func (e OrderEventName) String() string {
+	return string(e)
+}
+
+// GetEventName mengembalikan event yang dipublish saat order masuk ke status ini.
+func (p OrderStatus) GetEventName() (OrderEventName, bool) {
+	event, ok := mapStatusToOrderEvent[p]
+	return event, ok
+}
@@ -0,0 +1,14 @@
+package constants
+
+type OrderExpand string
+
+const (
+	OrderExpandFields  OrderExpand = "fields"
+	OrderExpandHistory OrderExpand = "history"
+)
+
+// OrderExpands berisi relasi yang boleh diminta lewat query ?expand= pada detail order.
+var OrderExpands = map[OrderExpand]bool{
+	OrderExpandFields:  true,
+	OrderExpandHistory: true,
+}
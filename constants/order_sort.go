@@ -0,0 +1,42 @@
+package constants
+
+import "strings"
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// OrderSortColumns memetakan nama kolom dari query string ke kolom database.
+// Hanya kolom di sini yang boleh dipakai untuk sorting.
+var OrderSortColumns = map[string]string{
+	"code":       "code",
+	"amount":     "amount",
+	"status":     "status",
+	"date":       "date",
+	"orderDate":  "date",
+	"paidAt":     "paid_at",
+	"paid_at":    "paid_at",
+	"createdAt":  "created_at",
+	"created_at": "created_at",
+	"updatedAt":  "updated_at",
+	"updated_at": "updated_at",
+}
+
+// SplitSortValues memecah nilai sort yang dipisah koma, misalnya "amount,createdAt".
+func SplitSortValues(value string) []string {
+	values := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func (s SortOrder) IsValid() bool {
+	return s == SortAsc || s == SortDesc
+}
@@ -0,0 +1,14 @@
+package constants
+
+// OrderTaskAction adalah efek samping order ke service lain yang dijalankan lewat antrean order_tasks.
+type OrderTaskAction string
+
+const (
+	BookFieldScheduleAction    OrderTaskAction = "book"
+	ReleaseFieldScheduleAction OrderTaskAction = "release"
+	VoidPaymentAction          OrderTaskAction = "void_payment"
+)
+
+func (a OrderTaskAction) String() string {
+	return string(a)
+}
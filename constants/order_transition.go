@@ -0,0 +1,21 @@
+package constants
+
+// OrderStatusTransitions berisi perpindahan status order yang diizinkan.
+// Status yang tidak punya entry dianggap final.
+var OrderStatusTransitions = map[OrderStatus][]OrderStatus{
+	Pending:        {PendingPayment, PaymentSuccess, Expired, Cancelled, Failed},
+	PendingPayment: {PaymentSuccess, Expired, Cancelled},
+}
+
+func (p OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, status := range OrderStatusTransitions[p] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (p OrderStatus) IsFinal() bool {
+	return len(OrderStatusTransitions[p]) == 0
+}
@@ -0,0 +1,67 @@
+package constants
+
+import "testing"
+
+func TestCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		allowed  bool
+	}{
+		{Pending, PendingPayment, true},
+		{Pending, PaymentSuccess, true},
+		{Pending, Expired, true},
+		{Pending, Cancelled, true},
+		{Pending, Failed, true},
+		{Pending, Pending, false},
+		{PendingPayment, PaymentSuccess, true},
+		{PendingPayment, Expired, true},
+		{PendingPayment, Cancelled, true},
+		{PendingPayment, Pending, false},
+		{PendingPayment, Failed, false},
+		{PaymentSuccess, Expired, false},
+		{PaymentSuccess, PendingPayment, false},
+		{PaymentSuccess, Cancelled, false},
+		{Expired, PaymentSuccess, false},
+		{Cancelled, PendingPayment, false},
+		{Failed, Pending, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from.GetStatusString())+"->"+string(tt.to.GetStatusString()), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+				t.Errorf("CanTransitionTo = %v, want %v", got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestCanTransitionToPaymentFlow(t *testing.T) {
+	flow := []OrderStatus{Pending, PendingPayment, PaymentSuccess}
+	for i := 1; i < len(flow); i++ {
+		if !flow[i-1].CanTransitionTo(flow[i]) {
+			t.Fatalf("%s -> %s rejected", flow[i-1].GetStatusString(), flow[i].GetStatusString())
+		}
+	}
+}
+
+func TestIsFinal(t *testing.T) {
+	tests := []struct {
+		status OrderStatus
+		final  bool
+	}{
+		{Pending, false},
+		{PendingPayment, false},
+		{PaymentSuccess, true},
+		{Expired, true},
+		{Cancelled, true},
+		{Failed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status.GetStatusString()), func(t *testing.T) {
+			if got := tt.status.IsFinal(); got != tt.final {
+				t.Errorf("IsFinal = %v, want %v", got, tt.final)
+			}
+		})
+	}
+}
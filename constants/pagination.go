@@ -0,0 +1,11 @@
+package constants
+
+const (
+	PaginationOffset = "offset"
+	PaginationCursor = "cursor"
+
+	CursorNext = "next"
+	CursorPrev = "prev"
+
+	DefaultCursorLimit = 10
+)
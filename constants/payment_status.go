@@ -7,3 +7,14 @@ const (
 	SettlementPaymentStatus PaymentStatusString = "settlement"
 	ExpiredPaymentStatus    PaymentStatusString = "expired"
 )
+
+var mapPaymentStatusToOrderStatus = map[PaymentStatusString]OrderStatus{
+	PendingPaymentStatus:    PendingPayment,
+	SettlementPaymentStatus: PaymentSuccess,
+	ExpiredPaymentStatus:    Expired,
+}
+
+func (p PaymentStatusString) GetOrderStatus() (OrderStatus, bool) {
+	status, ok := mapPaymentStatusToOrderStatus[p]
+	return status, ok
+}
@@ -0,0 +1,19 @@
+package constants
+
+type SagaStep string
+type SagaStepStatus string
+
+const (
+	CreateOrderStep       SagaStep = "create_order"
+	CreatePaymentLinkStep SagaStep = "create_payment_link"
+	AttachPaymentStep     SagaStep = "attach_payment"
+	VoidPaymentStep       SagaStep = "void_payment"
+	MarkOrderFailedStep   SagaStep = "mark_order_failed"
+
+	SagaStepCompleted SagaStepStatus = "completed"
+	SagaStepFailed    SagaStepStatus = "failed"
+)
+
+func (s SagaStep) String() string {
+	return string(s)
+}
@@ -8,11 +8,15 @@ const (
 	PendingPayment OrderStatus = 200
 	PaymentSuccess OrderStatus = 300
 	Expired        OrderStatus = 400
+	Cancelled      OrderStatus = 500
+	Failed         OrderStatus = 600
 
 	PendingString        OrderStatusString = "pending"
 	PendingPaymentString OrderStatusString = "pending_payment"
 	PaymentSuccessString OrderStatusString = "payment_success"
 	ExpiredString        OrderStatusString = "expired"
+	CancelledString      OrderStatusString = "cancelled"
+	FailedString         OrderStatusString = "failed"
 )
 
 var mapStatusStringtoInt = map[OrderStatusString]OrderStatus{
@@ -20,6 +24,8 @@ var mapStatusStringtoInt = map[OrderStatusString]OrderStatus{
 	PendingPaymentString: PendingPayment,
 	PaymentSuccessString: PaymentSuccess,
 	ExpiredString:        Expired,
+	CancelledString:      Cancelled,
+	FailedString:         Failed,
 }
 
 var mapStatusIntToString = map[OrderStatus]OrderStatusString{
@@ -27,6 +33,8 @@ var mapStatusIntToString = map[OrderStatus]OrderStatusString{
 	PendingPayment: PendingPaymentString,
 	PaymentSuccess: PaymentSuccessString,
 	Expired:        ExpiredString,
+	Cancelled:      CancelledString,
+	Failed:         FailedString,
 }
 
 func (p OrderStatusString) String() string {
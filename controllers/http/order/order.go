@@ -6,13 +6,14 @@ import (
 	"log"
 	"net/http"
 	"order-service/common/response"
+	"order-service/common/util"
+	errConstant "order-service/constants/error"
 	"order-service/domain/dto"
 	"order-service/services"
 
 	error2 "order-service/common/error"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 )
 
 type OrderController struct {
@@ -24,6 +25,7 @@ type IOrderController interface {
 	GetByUUID(ctx *gin.Context)
 	GetOrderByUserID(ctx *gin.Context)
 	Create(ctx *gin.Context)
+	Cancel(ctx *gin.Context)
 }
 
 func NewOrderController(service services.IServiceRegistry) *OrderController {
@@ -37,20 +39,18 @@ func (c *OrderController) GetAllWthPagination(ctx *gin.Context) {
 	err := ctx.ShouldBindQuery(&params)
 	if err != nil {
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusBadRequest,
-			Err:  err,
-			Gin:  ctx,
+			Err: errConstant.ErrBadRequest.Wrap(err),
+			Gin: ctx,
 		})
 		return
 	}
 
-	validate := validator.New()
+	validate := util.NewValidator()
 	if err = validate.Struct(params); err != nil {
 		errMessage := http.StatusText(http.StatusUnprocessableEntity)
 		errorResponse := error2.ErrValidationResponse(err)
 		response.HttpResponse(response.ParamHTTPResp{
-			Err:     err,
-			Code:    http.StatusUnprocessableEntity,
+			Err:     errConstant.ErrValidation.Wrap(err),
 			Message: &errMessage,
 			Data:    errorResponse,
 			Gin:     ctx,
@@ -58,12 +58,16 @@ func (c *OrderController) GetAllWthPagination(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.service.GetOrder().GetAllWithPagination(ctx, &params)
+	var result any
+	if params.IsCursorMode() {
+		result, err = c.service.GetOrder().GetAllWithCursor(ctx.Request.Context(), &params)
+	} else {
+		result, err = c.service.GetOrder().GetAllWithPagination(ctx.Request.Context(), &params)
+	}
 	if err != nil {
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusInternalServerError,
-			Err:  err,
-			Gin:  ctx,
+			Err: err,
+			Gin: ctx,
 		})
 		return
 	}
@@ -76,13 +80,35 @@ func (c *OrderController) GetAllWthPagination(ctx *gin.Context) {
 }
 
 func (c *OrderController) GetByUUID(ctx *gin.Context) {
+	var params dto.OrderDetailRequestParam
+	err := ctx.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Err: errConstant.ErrBadRequest.Wrap(err),
+			Gin: ctx,
+		})
+		return
+	}
+
+	validate := util.NewValidator()
+	if err = validate.Struct(params); err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := error2.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Err:     errConstant.ErrValidation.Wrap(err),
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     ctx,
+		})
+		return
+	}
+
 	uuid := ctx.Param("uuid")
-	result, err := c.service.GetOrder().GetByUUID(ctx, uuid)
+	result, err := c.service.GetOrder().GetByUUID(ctx.Request.Context(), uuid, &params)
 	if err != nil {
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusInternalServerError,
-			Err:  err,
-			Gin:  ctx,
+			Err: err,
+			Gin: ctx,
 		})
 		return
 	}
@@ -95,12 +121,34 @@ func (c *OrderController) GetByUUID(ctx *gin.Context) {
 }
 
 func (c *OrderController) GetOrderByUserID(ctx *gin.Context) {
-	result, err := c.service.GetOrder().GetOrdersByUserID(ctx)
+	var params dto.OrderHistoryRequestParam
+	err := ctx.ShouldBindQuery(&params)
 	if err != nil {
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusInternalServerError,
-			Err:  err,
-			Gin:  ctx,
+			Err: errConstant.ErrBadRequest.Wrap(err),
+			Gin: ctx,
+		})
+		return
+	}
+
+	validate := util.NewValidator()
+	if err = validate.Struct(params); err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := error2.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Err:     errConstant.ErrValidation.Wrap(err),
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     ctx,
+		})
+		return
+	}
+
+	result, err := c.service.GetOrder().GetOrdersByUserID(ctx.Request.Context(), &params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Err: err,
+			Gin: ctx,
 		})
 		return
 	}
@@ -135,9 +183,8 @@ func (o *OrderController) Create(c *gin.Context) {
 	if err != nil {
 		log.Printf("❌ Gagal bind JSON: %v\n", err)
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusBadRequest,
-			Err:  err,
-			Gin:  c,
+			Err: errConstant.ErrBadRequest.Wrap(err),
+			Gin: c,
 		})
 		return
 	}
@@ -145,15 +192,14 @@ func (o *OrderController) Create(c *gin.Context) {
 	log.Printf("✅ Berhasil bind JSON: %+v\n", request)
 
 	// Validasi
-	validate := validator.New()
+	validate := util.NewValidator()
 	if err = validate.Struct(request); err != nil {
 		log.Printf("❌ Validasi gagal: %v\n", err)
 		errMessage := http.StatusText(http.StatusUnprocessableEntity)
 		errorResponse := error2.ErrValidationResponse(err)
 
 		response.HttpResponse(response.ParamHTTPResp{
-			Err:     err,
-			Code:    http.StatusUnprocessableEntity,
+			Err:     errConstant.ErrValidation.Wrap(err),
 			Message: &errMessage,
 			Data:    errorResponse,
 			Gin:     c,
@@ -166,9 +212,8 @@ func (o *OrderController) Create(c *gin.Context) {
 	if err != nil {
 		log.Printf("❌ Gagal create order: %v\n", err)
 		response.HttpResponse(response.ParamHTTPResp{
-			Code: http.StatusBadRequest,
-			Err:  err,
-			Gin:  c,
+			Err: err,
+			Gin: c,
 		})
 		return
 	}
@@ -181,3 +226,21 @@ func (o *OrderController) Create(c *gin.Context) {
 		Gin:  c,
 	})
 }
+
+func (c *OrderController) Cancel(ctx *gin.Context) {
+	uuid := ctx.Param("uuid")
+	result, err := c.service.GetOrder().Cancel(ctx.Request.Context(), uuid)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Err: err,
+			Gin: ctx,
+		})
+		return
+	}
+
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  ctx,
+	})
+}
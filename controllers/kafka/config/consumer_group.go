@@ -2,7 +2,11 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	clientKafka "order-service/clients/kafka"
 	"order-service/config"
+	errConstant "order-service/constants/error"
+	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -15,12 +19,22 @@ type (
 )
 
 type ConsumerGroup struct {
-	handler map[TopicName]Handler
+	handler      map[TopicName]Handler
+	producer     clientKafka.IProducer
+	manualCommit bool
+
+	mu       sync.Mutex
+	draining bool
+	stopping chan struct{}
+	inflight sync.WaitGroup
 }
 
-func NewConsumerGroup() *ConsumerGroup {
+func NewConsumerGroup(producer clientKafka.IProducer) *ConsumerGroup {
 	return &ConsumerGroup{
-		handler: make(map[TopicName]Handler),
+		handler:      make(map[TopicName]Handler),
+		producer:     producer,
+		manualCommit: IsManualCommit(),
+		stopping:     make(chan struct{}),
 	}
 }
 
@@ -35,41 +49,162 @@ func (c *ConsumerGroup) Cleanup(sarama sarama.ConsumerGroupSession) error {
 }
 
 func (c *ConsumerGroup) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	messages := claim.Messages()
-	for message := range messages {
-		handler, ok := c.handler[TopicName(message.Topic)]
-		if !ok {
-			logrus.Warnf("No handler for topic %s", message.Topic)
-			continue
+	for {
+		var message *sarama.ConsumerMessage
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			message = msg
+		case <-c.stopping:
+			// tunggu session berakhir, kembali lebih awal akan membatalkan claim lain yang masih memproses message
+			<-session.Context().Done()
+			return nil
+		case <-session.Context().Done():
+			// rebalance atau shutdown, message berikutnya diambil oleh session baru
+			return nil
 		}
 
-		var err error
-		maxRetry := config.Config.Kafka.MaxRetry
-		for attempt := 1; attempt <= maxRetry; attempt++ {
-			err = handler(context.Background(), message)
-			if err == nil {
-				break
-			}
+		if !c.begin() {
+			// sedang drain, message ini belum di-mark sehingga akan dibaca ulang
+			<-session.Context().Done()
+			return nil
+		}
 
-			logrus.Errorf("Error handling message from topic %s, attempt %d/%d: %v", message.Topic, attempt, maxRetry, err)
-			if attempt == maxRetry {
-				logrus.Errorf("Max retry reached for message from topic %s: %v", message.Topic, err)
-			}
+		done := c.process(session, message)
+		c.inflight.Done()
+		if !done {
+			return nil
 		}
+	}
+}
+
+// process mengembalikan false jika message belum selesai karena session berakhir.
+func (c *ConsumerGroup) process(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) bool {
+	handler, ok := c.handler[TopicName(message.Topic)]
+	if !ok {
+		logrus.Warnf("No handler for topic %s", message.Topic)
+		return true
+	}
 
-		if err != nil {
-			logrus.Errorf("Failed to process message from topic %s after %d attempts: %v", message.Topic, maxRetry, err)
-			session.MarkMessage(message, err.Error())
-			break
+	ctx := session.Context()
+	attempts, err := c.handle(ctx, handler, message)
+	if ctx.Err() != nil {
+		// message belum selesai, jangan di-mark supaya diproses ulang setelah rebalance
+		return false
+	}
+
+	if err != nil {
+		logrus.Errorf("Failed to process message from topic %s after %d attempts: %v", message.Topic, attempts, err)
+		if !c.sendToDeadLetter(ctx, message, attempts, err) {
+			return false
 		}
+	}
 
-		session.MarkMessage(message, time.Now().UTC().String())
+	session.MarkMessage(message, time.Now().UTC().String())
+	if c.manualCommit {
+		// commit hanya setelah handler atau DLQ selesai, supaya offset tidak mendahului transaksi DB
+		session.Commit()
 	}
 
-	return nil
+	return true
+}
+
+func (c *ConsumerGroup) begin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return false
+	}
+
+	c.inflight.Add(1)
+	return true
+}
+
+// Drain berhenti mengambil message baru dan menunggu message yang sedang diproses selesai.
+func (c *ConsumerGroup) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.draining {
+		c.draining = true
+		close(c.stopping)
+	}
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handle menjalankan handler sampai MaxRetry kali dengan jeda exponential backoff di antara percobaan.
+func (c *ConsumerGroup) handle(ctx context.Context, handler Handler, message *sarama.ConsumerMessage) (int, error) {
+	var (
+		err      error
+		maxRetry = max(config.Config.Kafka.MaxRetry, 1)
+	)
+
+	for attempt := 1; attempt <= maxRetry; attempt++ {
+		err = handler(ctx, message)
+		if err == nil {
+			return attempt, nil
+		}
+
+		logrus.Errorf("Error handling message from topic %s, attempt %d/%d: %v", message.Topic, attempt, maxRetry, err)
+		// message yang tidak sesuai schema atau tidak bisa diterapkan tidak akan berhasil walaupun diulang
+		if attempt == maxRetry || errors.Is(err, errConstant.ErrInvalidMessage) || errors.Is(err, errConstant.ErrUnprocessable) {
+			return attempt, err
+		}
+
+		if !sleep(ctx, retryBackoff(attempt)) {
+			return attempt, err
+		}
+	}
+
+	return maxRetry, err
 }
 
 func (c *ConsumerGroup) RegisterHandler(topic TopicName, handler Handler) {
 	c.handler[topic] = handler
 	logrus.Infof("Handler registered for topic %s", topic)
 }
+
+func retryBackoff(attempt int) time.Duration {
+	base := time.Duration(config.Config.Kafka.RetryBackoffInMs) * time.Millisecond
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+
+	maxBackoff := time.Duration(config.Config.Kafka.RetryMaxBackoffInMs) * time.Millisecond
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+
+	backoff := base << (attempt - 1)
+	if backoff <= 0 || backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// sleep menunggu selama d, mengembalikan false jika ctx lebih dulu dibatalkan.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"order-service/config"
+	"order-service/domain/dto"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultDeadLetterSuffix = ".dlq"
+
+func DeadLetterTopic(topic string) string {
+	suffix := config.Config.Kafka.DeadLetterSuffix
+	if suffix == "" {
+		suffix = defaultDeadLetterSuffix
+	}
+	return topic + suffix
+}
+
+// sendToDeadLetter memindahkan message yang gagal ke topic DLQ. Publish diulang sampai berhasil
+// karena message asli baru boleh di-mark setelah tersimpan di DLQ. Mengembalikan false
+// jika session berakhir sebelum publish berhasil.
+func (c *ConsumerGroup) sendToDeadLetter(ctx context.Context, message *sarama.ConsumerMessage, attempts int, cause error) bool {
+	payload := json.RawMessage(message.Value)
+	if !json.Valid(message.Value) {
+		payload, _ = json.Marshal(string(message.Value))
+	}
+
+	value, err := json.Marshal(dto.DeadLetterMessage{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       string(message.Key),
+		Payload:   payload,
+		Error:     cause.Error(),
+		Attempts:  attempts,
+		FailedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		logrus.Errorf("Failed to encode dead letter for topic %s offset %d: %v", message.Topic, message.Offset, err)
+		return false
+	}
+
+	topic := DeadLetterTopic(message.Topic)
+	for attempt := 1; ; attempt++ {
+		err = c.producer.Publish(topic, string(message.Key), value)
+		if err == nil {
+			logrus.Warnf("Message from topic %s partition %d offset %d moved to %s", message.Topic, message.Partition, message.Offset, topic)
+			return true
+		}
+
+		logrus.Errorf("Failed to publish dead letter to %s, attempt %d: %v", topic, attempt, err)
+		if !sleep(ctx, retryBackoff(attempt)) {
+			return false
+		}
+	}
+}
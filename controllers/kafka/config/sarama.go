@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"fmt"
+	"order-service/config"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	CommitModeManual = "manual"
+	CommitModeAuto   = "auto"
+
+	defaultAutoCommitInterval = time.Second
+)
+
+// IsManualCommit bernilai true kecuali config.Kafka.CommitMode diisi "auto".
+func IsManualCommit() bool {
+	return !strings.EqualFold(config.Config.Kafka.CommitMode, CommitModeAuto)
+}
+
+// NewSaramaConfig menyusun konfigurasi consumer dari config.Kafka.
+func NewSaramaConfig() (*sarama.Config, error) {
+	kafkaConfig := config.Config.Kafka
+
+	consumerConfig := sarama.NewConfig()
+	consumerConfig.Consumer.MaxWaitTime = time.Duration(kafkaConfig.MaxWaitTimeInMs) * time.Millisecond
+	consumerConfig.Consumer.MaxProcessingTime = time.Duration(kafkaConfig.MaxProcessingTimeInMs) * time.Millisecond
+	consumerConfig.Consumer.Retry.Backoff = time.Duration(kafkaConfig.BackoffTimeInMs) * time.Millisecond
+
+	switch strings.ToLower(kafkaConfig.OffsetInitial) {
+	case "", "oldest":
+		consumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	case "newest":
+		consumerConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	default:
+		return nil, fmt.Errorf("unknown kafka offsetInitial %q, expected oldest or newest", kafkaConfig.OffsetInitial)
+	}
+
+	switch strings.ToLower(kafkaConfig.RebalanceStrategy) {
+	case "", "roundrobin":
+		consumerConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+	case "range":
+		consumerConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRange()}
+	case "sticky":
+		consumerConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategySticky()}
+	default:
+		return nil, fmt.Errorf("unknown kafka rebalanceStrategy %q, expected roundrobin, range or sticky", kafkaConfig.RebalanceStrategy)
+	}
+
+	switch strings.ToLower(kafkaConfig.CommitMode) {
+	case "", CommitModeManual:
+		consumerConfig.Consumer.Offsets.AutoCommit.Enable = false
+	case CommitModeAuto:
+		consumerConfig.Consumer.Offsets.AutoCommit.Enable = true
+		consumerConfig.Consumer.Offsets.AutoCommit.Interval = defaultAutoCommitInterval
+		if kafkaConfig.AutoCommitIntervalInMs > 0 {
+			consumerConfig.Consumer.Offsets.AutoCommit.Interval = time.Duration(kafkaConfig.AutoCommitIntervalInMs) * time.Millisecond
+		}
+	default:
+		return nil, fmt.Errorf("unknown kafka commitMode %q, expected manual or auto", kafkaConfig.CommitMode)
+	}
+
+	return consumerConfig, nil
+}
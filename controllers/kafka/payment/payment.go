@@ -2,9 +2,11 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"order-service/common/util"
-	"order-service/domain/dto"
+	errConstant "order-service/constants/error"
+	errOrder "order-service/constants/error/order"
+	"order-service/schemas"
 	"order-service/services"
 
 	"github.com/IBM/sarama"
@@ -29,16 +31,27 @@ func NewPaymentKafka(service services.IServiceRegistry) IPaymentKafka {
 
 func (p *PaymentKafka) HandlePayment(ctx context.Context, msg *sarama.ConsumerMessage) error {
 	defer util.Recover()
-	var body dto.PaymentContent
 
-	err := json.Unmarshal(msg.Value, &body)
+	body, err := schemas.DecodePayment(msg.Value)
 	if err != nil {
-		logrus.Error("[PaymentKafka-HandlePayment] error when unmarshal message: ", err)
+		logrus.Error("[PaymentKafka-HandlePayment] invalid payment message: ", err)
 		return err
 	}
 
 	data := body.Body.Data
 	err = p.service.GetOrder().HandlePayment(ctx, &data)
+	if errors.Is(err, errOrder.ErrPaymentOnClosedOrder) {
+		// uang sudah diterima tapi order sudah tutup, kirim ke DLQ supaya bisa di-refund atau diproses manual
+		logrus.Errorf("[PaymentKafka-HandlePayment] settlement for closed order %s: %v", data.OrderID, err)
+		return errConstant.ErrUnprocessable.Wrap(err)
+	}
+
+	if errors.Is(err, errOrder.ErrInvalidStatusTransition) || errors.Is(err, errOrder.ErrUnknownPaymentStatus) {
+		// tidak perlu retry, message yang sama akan selalu ditolak
+		logrus.Warnf("[PaymentKafka-HandlePayment] skip payment event for order %s: %v", data.OrderID, err)
+		return nil
+	}
+
 	if err != nil {
 		logrus.Error("[PaymentKafka-HandlePayments] error when handle payment: ", err)
 		return err
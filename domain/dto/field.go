@@ -1,5 +1,16 @@
 package dto
 
+import "time"
+
+// HoldID adalah kode order pemilik hold, supaya field service hanya mem-book atau melepas
+// jadwal yang memang ditahan oleh order tersebut. Task lama tanpa hold id mengirim string kosong.
 type UpdateFieldScheduleStatusRequest struct {
 	FieldScheduleIDs []string `json:"fieldScheduleIDs"`
+	HoldID           string   `json:"holdID,omitempty"`
+}
+
+type HoldFieldScheduleRequest struct {
+	FieldScheduleIDs []string  `json:"fieldScheduleIDs"`
+	HoldID           string    `json:"holdID"`
+	ExpiredAt        time.Time `json:"expiredAt"`
 }
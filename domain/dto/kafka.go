@@ -1,12 +1,19 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type KafkaEvent struct {
-	Name string `json:"name"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
 }
 
 type KafkaMetaData struct {
 	Sender    string `json:"sender"`
 	SendingAt string `json:"sending_at"`
+	Sequence  *int64 `json:"sequence,omitempty"`
 }
 
 type DataType string
@@ -21,3 +28,16 @@ type KafkaMessage[T any] struct {
 	MetaData KafkaMetaData `json:"meta_data"`
 	Body     KafkaBody[T]  `json:"body"`
 }
+
+// DeadLetterMessage membungkus message yang gagal diproses beserta informasi kegagalannya,
+// supaya bisa diperiksa dan diproses ulang dari topic DLQ.
+type DeadLetterMessage struct {
+	Topic     string          `json:"topic"`
+	Partition int32           `json:"partition"`
+	Offset    int64           `json:"offset"`
+	Key       string          `json:"key"`
+	Payload   json.RawMessage `json:"payload"`
+	Error     string          `json:"error"`
+	Attempts  int             `json:"attempts"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
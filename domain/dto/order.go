@@ -8,14 +8,55 @@ import (
 )
 
 type OrderRequest struct {
-	FieldScheduleIDs []string `json:"fieldScheduleIDs" validate:"required"`
+	FieldScheduleIDs []string `json:"fieldScheduleIDs" validate:"required,min=1,unique,dive,uuid"`
 }
 
 type OrderRequestParam struct {
-	Page       int     `json:"page" validate:"required"`
-	Limit      int     `json:"limit" validate:"required"`
-	SortColumn *string `json:"sortColumn"`
-	SortOrder  *string `json:"sortOrder"`
+	Page       int     `json:"page" form:"page" validate:"required_unless=Pagination cursor"`
+	Limit      int     `json:"limit" form:"limit" validate:"required"`
+	Pagination string  `json:"pagination" form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     *string `json:"cursor" form:"cursor"`
+	Direction  string  `json:"direction" form:"direction" validate:"omitempty,oneof=next prev"`
+	// mode cursor selalu diurutkan berdasarkan createdAt dan id, sort lain ditolak
+	SortColumn *string `json:"sortColumn" form:"sortColumn" validate:"excluded_if=Pagination cursor,omitempty,sortcolumn"`
+	SortOrder  *string `json:"sortOrder" form:"sortOrder" validate:"excluded_if=Pagination cursor,omitempty,sortorder"`
+
+	Status     *constants.OrderStatusString `json:"status" form:"status" validate:"omitempty,oneof=pending pending_payment payment_success expired cancelled failed"`
+	IsPaid     *bool                        `json:"isPaid" form:"isPaid"`
+	UserUUID   *string                      `json:"userUUID" form:"userUUID" validate:"omitempty,uuid"`
+	Code       *string                      `json:"code" form:"code" validate:"omitempty,max=30"`
+	DateFrom   *time.Time                   `json:"dateFrom" form:"dateFrom" time_format:"2006-01-02"`
+	DateTo     *time.Time                   `json:"dateTo" form:"dateTo" time_format:"2006-01-02"`
+	PaidAtFrom *time.Time                   `json:"paidAtFrom" form:"paidAtFrom" time_format:"2006-01-02"`
+	PaidAtTo   *time.Time                   `json:"paidAtTo" form:"paidAtTo" time_format:"2006-01-02"`
+	MinAmount  *float64                     `json:"minAmount" form:"minAmount" validate:"omitempty,gte=0"`
+	MaxAmount  *float64                     `json:"maxAmount" form:"maxAmount" validate:"omitempty,gte=0"`
+
+	// diisi oleh service untuk membatasi customer ke order miliknya, bukan dari query string
+	UserID *uuid.UUID `json:"-" form:"-"`
+}
+
+func (p *OrderRequestParam) IsCursorMode() bool {
+	return p.Pagination == constants.PaginationCursor
+}
+
+type OrderHistoryRequestParam struct {
+	Limit     int     `json:"limit" form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor    *string `json:"cursor" form:"cursor"`
+	Direction string  `json:"direction" form:"direction" validate:"omitempty,oneof=next prev"`
+}
+
+type OrderDetailRequestParam struct {
+	Expand string `json:"expand" form:"expand" validate:"omitempty,orderexpand"`
+}
+
+func (p *OrderDetailRequestParam) Has(expand constants.OrderExpand) bool {
+	for _, item := range constants.SplitSortValues(p.Expand) {
+		if constants.OrderExpand(item) == expand {
+			return true
+		}
+	}
+	return false
 }
 
 type OrderResponse struct {
@@ -28,6 +69,9 @@ type OrderResponse struct {
 	OrderDate   time.Time                   `json:"orderDate"`
 	CreatedAt   time.Time                   `json:"createdAt"`
 	UpdatedAt   time.Time                   `json:"updatedAt"`
+
+	Fields    []OrderFieldResponse   `json:"fields,omitempty"`
+	Histories []OrderHistoryResponse `json:"histories,omitempty"`
 }
 
 type OrderByUserIDResponse struct {
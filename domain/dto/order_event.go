@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"order-service/constants"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const OrderDataType DataType = "order"
+
+type OrderEventData struct {
+	UUID      uuid.UUID                   `json:"uuid"`
+	Code      string                      `json:"code"`
+	UserID    uuid.UUID                   `json:"user_id"`
+	PaymentID uuid.UUID                   `json:"payment_id"`
+	Amount    float64                     `json:"amount"`
+	Status    constants.OrderStatusString `json:"status"`
+	IsPaid    bool                        `json:"is_paid"`
+	OrderDate time.Time                   `json:"order_date"`
+	PaidAt    *time.Time                  `json:"paid_at"`
+}
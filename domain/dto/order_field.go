@@ -1,6 +1,19 @@
 package dto
 
+import "github.com/google/uuid"
+
 type OrderFieldRequest struct {
 	OrderID         uint
 	FieldScheduleID string
 }
+
+type OrderFieldResponse struct {
+	FieldScheduleID uuid.UUID  `json:"fieldScheduleID"`
+	FieldID         *uuid.UUID `json:"fieldID,omitempty"`
+	FieldName       string     `json:"fieldName"`
+	Date            string     `json:"date"`
+	StartTime       string     `json:"startTime"`
+	EndTime         string     `json:"endTime"`
+	Price           float64    `json:"price"`
+	IsActive        bool       `json:"isActive"`
+}
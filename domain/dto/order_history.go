@@ -1,8 +1,18 @@
 package dto
 
-import "order-service/constants"
+import (
+	"order-service/constants"
+	"time"
+)
 
 type OrderHistoryRequest struct {
 	OrderID uint
 	Status  constants.OrderStatusString
+	Remark  *string
+}
+
+type OrderHistoryResponse struct {
+	Status    constants.OrderStatusString `json:"status"`
+	Remark    *string                     `json:"remark,omitempty"`
+	CreatedAt *time.Time                  `json:"createdAt"`
 }
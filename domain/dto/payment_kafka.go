@@ -8,11 +8,17 @@ import (
 )
 
 type PaymentData struct {
-	OrderID   uuid.UUID                     `json:"order_id"`
-	PaymentID uuid.UUID                     `json:"payment_id"`
-	Status    constants.PaymentStatusString `json:"status"`
-	ExpiredAt *time.Time                    `json:"expired_at"`
-	PaidAt    *time.Time                    `json:"paid_at"`
+	OrderID     uuid.UUID                     `json:"order_id"`
+	PaymentID   uuid.UUID                     `json:"payment_id"`
+	Status      constants.PaymentStatusString `json:"status"`
+	ExpiredAt   *time.Time                    `json:"expired_at"`
+	PaidAt      *time.Time                    `json:"paid_at"`
+	PaymentLink *string                       `json:"payment_link,omitempty"`
+	InvoiceLink *string                       `json:"invoice_link,omitempty"`
+
+	// diisi dari metadata message, dipakai untuk mengabaikan event yang datang terlambat
+	SentAt   *time.Time `json:"-"`
+	Sequence *int64     `json:"-"`
 }
 
 type PaymentContent struct {
@@ -20,3 +26,62 @@ type PaymentContent struct {
 	Metadata KafkaMetaData          `json:"metadata"`
 	Body     KafkaBody[PaymentData] `json:"body"`
 }
+
+// PaymentDataV2 adalah payload payment callback versi v2 yang memakai camelCase.
+type PaymentDataV2 struct {
+	OrderID     uuid.UUID                     `json:"orderId"`
+	PaymentID   uuid.UUID                     `json:"paymentId"`
+	Status      constants.PaymentStatusString `json:"status"`
+	ExpiredAt   *time.Time                    `json:"expiredAt"`
+	PaidAt      *time.Time                    `json:"paidAt"`
+	PaymentLink *string                       `json:"paymentLink,omitempty"`
+	InvoiceLink *string                       `json:"invoiceLink,omitempty"`
+}
+
+type KafkaMetaDataV2 struct {
+	Sender    string `json:"sender"`
+	SendingAt string `json:"sendingAt"`
+	Sequence  *int64 `json:"sequence,omitempty"`
+}
+
+type PaymentContentV2 struct {
+	Event    KafkaEvent               `json:"event"`
+	Metadata KafkaMetaDataV2          `json:"metadata"`
+	Body     KafkaBody[PaymentDataV2] `json:"body"`
+}
+
+// ToV1 mengubah message v2 ke bentuk yang dipakai service.
+func (p PaymentContentV2) ToV1() PaymentContent {
+	return PaymentContent{
+		Event: p.Event,
+		Metadata: KafkaMetaData{
+			Sender:    p.Metadata.Sender,
+			SendingAt: p.Metadata.SendingAt,
+			Sequence:  p.Metadata.Sequence,
+		},
+		Body: KafkaBody[PaymentData]{
+			Type: p.Body.Type,
+			Data: PaymentData{
+				OrderID:     p.Body.Data.OrderID,
+				PaymentID:   p.Body.Data.PaymentID,
+				Status:      p.Body.Data.Status,
+				ExpiredAt:   p.Body.Data.ExpiredAt,
+				PaidAt:      p.Body.Data.PaidAt,
+				PaymentLink: p.Body.Data.PaymentLink,
+				InvoiceLink: p.Body.Data.InvoiceLink,
+			},
+		},
+	}
+}
+
+// PaymentPlan menjelaskan efek sebuah payment event terhadap order tanpa mengubah data,
+// dipakai oleh redrive dalam mode dry-run.
+type PaymentPlan struct {
+	OrderID       uuid.UUID                     `json:"order_id"`
+	PaymentID     uuid.UUID                     `json:"payment_id"`
+	PaymentStatus constants.PaymentStatusString `json:"payment_status"`
+	CurrentStatus constants.OrderStatusString   `json:"current_status,omitempty"`
+	TargetStatus  constants.OrderStatusString   `json:"target_status,omitempty"`
+	WillChange    bool                          `json:"will_change"`
+	Reason        string                        `json:"reason,omitempty"`
+}
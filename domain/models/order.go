@@ -10,7 +10,7 @@ import (
 type Order struct {
 	ID        uint                  `gorm:"primaryKey;autoIncrement"`
 	UUID      uuid.UUID             `gorm:"type:uuid;not null"`
-	Code      string                `gorm:"type:varchar(30);not null"`
+	Code      string                `gorm:"type:varchar(30);not null;uniqueIndex"`
 	UserID    uuid.UUID             `gorm:"type:uuid;not null"`
 	PaymentID uuid.UUID             `gorm:"type:uuid;not null"`
 	Amount    float64               `gorm:"type:decimal(10,2);not null"`
@@ -18,6 +18,13 @@ type Order struct {
 	Date      time.Time             `gorm:"type:timestamp;not null"`
 	IsPaid    bool                  `gorm:"not null;default:false"`
 	PaidAt    *time.Time            `gorm:"type:timestamp;"`
-	CreatedAt *time.Time            `gorm:"autoCreateTime"`
-	UpdatedAt *time.Time            `gorm:"autoUpdateTime"`
+	ExpiredAt *time.Time            `gorm:"type:timestamp;index"`
+	// link dari payment service disimpan supaya riwayat order tidak perlu memanggil payment service
+	PaymentLink *string `gorm:"type:text"`
+	InvoiceLink *string `gorm:"type:text"`
+	// posisi payment event terakhir yang diterapkan, event yang lebih lama diabaikan
+	LastPaymentEventAt  *time.Time `gorm:"type:timestamp"`
+	LastPaymentEventSeq *int64     `gorm:"type:bigint"`
+	CreatedAt           *time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           *time.Time `gorm:"autoUpdateTime"`
 }
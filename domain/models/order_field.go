@@ -9,7 +9,16 @@ import (
 type OrderField struct {
 	ID              uint      `gorm:"primaryKey;autoIncrement"`
 	OrderID         uint      `gorm:"type:bigint;not null"`
-	FieldScheduleID uuid.UUID `gorm:"type:uuid;not null"`
-	CreatedAt       *time.Time
-	UpdatedAt       *time.Time
+	FieldScheduleID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_order_fields_active_schedule,where:is_active = true"`
+	IsActive        bool      `gorm:"not null;default:false"` // default false supaya data lama tidak bentrok dengan unique index
+
+	// snapshot jadwal saat order dibuat, dipakai untuk menjelaskan total amount walaupun harga di field service berubah
+	FieldID   *uuid.UUID `gorm:"type:uuid"`
+	FieldName string     `gorm:"type:varchar(255);not null;default:''"`
+	Date      string     `gorm:"type:varchar(20);not null;default:''"`
+	StartTime string     `gorm:"type:varchar(10);not null;default:''"`
+	EndTime   string     `gorm:"type:varchar(10);not null;default:''"`
+	UnitPrice float64    `gorm:"type:decimal(10,2);not null;default:0"`
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
 }
@@ -9,6 +9,7 @@ type OrderHistory struct {
 	ID        uint                        `gorm:"primaryKey;autoIncrement"`
 	OrderID   uint                        `gorm:"type:bigint;not null"`
 	Status    constants.OrderStatusString `gorm:"type:varchar(30);not null"`
+	Remark    *string                     `gorm:"type:text"`
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
 }
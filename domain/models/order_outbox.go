@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderOutbox struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	OrderID     uint       `gorm:"type:bigint;not null"`
+	OrderUUID   uuid.UUID  `gorm:"type:uuid;not null"`
+	EventName   string     `gorm:"type:varchar(50);not null"`
+	Payload     string     `gorm:"type:jsonb;not null"`
+	Attempts    int        `gorm:"not null;default:0"`
+	LastError   *string    `gorm:"type:text"`
+	PublishedAt *time.Time `gorm:"type:timestamp;index"`
+	// FailedAt diisi untuk event yang tidak akan pernah bisa dipublikasikan, relay melewatinya
+	FailedAt  *time.Time `gorm:"type:timestamp;index"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+	UpdatedAt *time.Time `gorm:"autoUpdateTime"`
+}
+
+func (OrderOutbox) TableName() string {
+	return "order_outbox"
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"order-service/constants"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderSagaStep struct {
+	ID        uint                     `gorm:"primaryKey;autoIncrement"`
+	OrderID   uint                     `gorm:"type:bigint;not null;index"`
+	Step      constants.SagaStep       `gorm:"type:varchar(50);not null"`
+	Status    constants.SagaStepStatus `gorm:"type:varchar(20);not null"`
+	Error     *string                  `gorm:"type:text"`
+	PaymentID *uuid.UUID               `gorm:"type:uuid"` // diisi di create_payment_link supaya payment bisa di-void saat recovery
+	CreatedAt *time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"order-service/constants"
+	"time"
+)
+
+// OrderTask mencatat efek samping order ke service lain (book/release jadwal, void payment)
+// yang dijalankan setelah commit dan dicoba ulang oleh worker kalau gagal.
+type OrderTask struct {
+	ID            uint                      `gorm:"primaryKey;autoIncrement"`
+	OrderID       uint                      `gorm:"type:bigint;not null;index"`
+	Action        constants.OrderTaskAction `gorm:"type:varchar(20);not null"`
+	Payload       string                    `gorm:"type:jsonb;not null"`
+	Attempts      int                       `gorm:"not null;default:0"`
+	LastError     *string                   `gorm:"type:text"`
+	NextAttemptAt time.Time                 `gorm:"type:timestamp;not null;index"`
+	CompletedAt   *time.Time                `gorm:"type:timestamp"`
+	CreatedAt     *time.Time                `gorm:"autoCreateTime"`
+	UpdatedAt     *time.Time                `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type ProcessedEvent struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	EventKey  string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	OrderID   uint   `gorm:"type:bigint;not null"`
+	CreatedAt *time.Time
+}
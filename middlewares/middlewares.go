@@ -26,8 +26,9 @@ func HandlePanic() gin.HandlerFunc {
 			if r := recover(); r != nil {
 				logrus.Errorf("🔥 Recovered from panic: %v\n%s", r, debug.Stack())
 				c.JSON(http.StatusInternalServerError, response.Response{
-					Status:  constants.Error,
-					Message: errConstant.ErrInternalServerError.Error(),
+					Status:    constants.Error,
+					Message:   errConstant.ErrInternalServerError.Error(),
+					ErrorCode: errConstant.ErrInternalServerError.Code,
 				})
 				c.Abort()
 			}
@@ -42,8 +43,9 @@ func RateLimiter(lmt *limiter.Limiter) gin.HandlerFunc {
 		if err != nil {
 			logrus.Warnf("🚦 Rate limit triggered: %v", err)
 			c.JSON(http.StatusTooManyRequests, response.Response{
-				Status:  constants.Error,
-				Message: errConstant.ErrToManyRequests.Error(),
+				Status:    constants.Error,
+				Message:   errConstant.ErrToManyRequests.Error(),
+				ErrorCode: errConstant.ErrToManyRequests.Code,
 			})
 			c.Abort()
 			return
@@ -63,8 +65,9 @@ func extractBearerToken(token string) string {
 func responseUnauthorized(c *gin.Context, message string) {
 	logrus.Warnf("🔒 Unauthorized: %s", message)
 	c.JSON(http.StatusUnauthorized, response.Response{
-		Status:  constants.Error,
-		Message: message,
+		Status:    constants.Error,
+		Message:   message,
+		ErrorCode: errConstant.ErrUnauthorized.Code,
 	})
 	c.Abort()
 }
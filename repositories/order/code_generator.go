@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+
+	"gorm.io/gorm"
+)
+
+const (
+	orderCodeSequence = "order_code_seq"
+
+	// DefaultCodeFormat menerima argumen nomor urut lalu tanggal (YYYYMMDD).
+	DefaultCodeFormat = "ORD-%05d-%s"
+)
+
+// sequence yang sudah pasti ada, supaya CREATE SEQUENCE tidak dijalankan di setiap order
+var createdSequences sync.Map
+
+type ICodeGenerator interface {
+	Generate(context.Context) (string, error)
+}
+
+type SequenceCodeGenerator struct {
+	db         *gorm.DB
+	format     string
+	resetDaily bool
+}
+
+func NewSequenceCodeGenerator(db *gorm.DB, format string, resetDaily bool) ICodeGenerator {
+	if format == "" {
+		format = DefaultCodeFormat
+	}
+
+	return &SequenceCodeGenerator{
+		db:         db,
+		format:     format,
+		resetDaily: resetDaily,
+	}
+}
+
+func (s *SequenceCodeGenerator) Generate(ctx context.Context) (string, error) {
+	var (
+		next     int64
+		today    = time.Now().Format("20060102")
+		sequence = orderCodeSequence
+	)
+
+	if s.resetDaily {
+		sequence = fmt.Sprintf("%s_%s", orderCodeSequence, today)
+	}
+
+	err := s.ensureSequence(ctx, sequence)
+	if err != nil {
+		return "", err
+	}
+
+	// nextval tidak ikut transaksi, jadi aman dipanggil bersamaan dan tidak pernah memberi nilai yang sama
+	err = s.db.WithContext(ctx).Raw("SELECT nextval(?)", sequence).Scan(&next).Error
+	if err != nil {
+		return "", errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return fmt.Sprintf(s.format, next, today), nil
+}
+
+func (s *SequenceCodeGenerator) ensureSequence(ctx context.Context, sequence string) error {
+	if _, ok := createdSequences.Load(sequence); ok {
+		return nil
+	}
+
+	err := s.db.WithContext(ctx).Exec(fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s", sequence)).Error
+	if err != nil {
+		// CREATE SEQUENCE IF NOT EXISTS bisa bentrok kalau dua request membuat sequence yang sama
+		var exists bool
+		checkErr := s.db.WithContext(ctx).
+			Raw("SELECT EXISTS (SELECT 1 FROM pg_class WHERE relkind = 'S' AND relname = ?)", sequence).
+			Scan(&exists).Error
+		if checkErr != nil || !exists {
+			return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+		}
+	}
+
+	createdSequences.Store(sequence, struct{}{})
+	return nil
+}
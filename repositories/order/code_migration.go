@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"order-service/domain/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// legacyCodePattern mengambil nomor urut dari kode yang dibuat generator lama (ORD-%05d-YYYYMMDD).
+const legacyCodePattern = `^ORD-([0-9]+)-([0-9]{8})$`
+
+// DedupeCodes mengganti kode order yang duplikat sebelum unique index di orders.code dibuat.
+// Order paling awal tetap memakai kode aslinya, sisanya diberi akhiran id supaya tetap bisa dilacak.
+// Dijalankan sebelum AutoMigrate, hanya selama unique index belum ada.
+func DedupeCodes(ctx context.Context, db *gorm.DB) error {
+	migrator := db.WithContext(ctx).Migrator()
+	if !migrator.HasTable(&models.Order{}) || migrator.HasIndex(&models.Order{}, "Code") {
+		return nil
+	}
+
+	result := db.WithContext(ctx).Exec(`
+		UPDATE orders o
+		SET code = LEFT(o.code, 29 - LENGTH(o.id::text)) || '-' || o.id
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY code ORDER BY id) AS rn
+			FROM orders
+		) d
+		WHERE o.id = d.id AND d.rn > 1`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		logrus.Warnf("[DedupeCodes] renamed %d orders with duplicated codes", result.RowsAffected)
+	}
+
+	return nil
+}
+
+// SeedCodeSequence menaikkan sequence kode order melewati nomor urut kode lama, supaya generator
+// tidak mengulang kode yang sudah dipakai generator sebelumnya. Sequence tidak pernah dimundurkan.
+func SeedCodeSequence(ctx context.Context, db *gorm.DB, resetDaily bool) error {
+	var (
+		today    = time.Now().Format("20060102")
+		sequence = orderCodeSequence
+		maxCode  int64
+	)
+
+	query := db.WithContext(ctx).Model(&models.Order{}).
+		Select("COALESCE(MAX(CAST(SUBSTRING(code FROM ?) AS bigint)), 0)", legacyCodePattern).
+		Where("code ~ ?", legacyCodePattern)
+	if resetDaily {
+		// sequence harian hanya bisa bentrok dengan kode lama bertanggal hari ini
+		sequence = fmt.Sprintf("%s_%s", orderCodeSequence, today)
+		query = query.Where("code LIKE ?", "%-"+today)
+	}
+
+	err := query.Scan(&maxCode).Error
+	if err != nil {
+		return err
+	}
+
+	if maxCode == 0 {
+		return nil
+	}
+
+	generator := &SequenceCodeGenerator{db: db}
+	err = generator.ensureSequence(ctx, sequence)
+	if err != nil {
+		return err
+	}
+
+	err = db.WithContext(ctx).Exec(fmt.Sprintf(
+		"SELECT setval('%[1]s', GREATEST(?, (SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM %[1]s)))",
+		sequence,
+	), maxCode).Error
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("[SeedCodeSequence] %s starts after %d", sequence, maxCode)
+	return nil
+}
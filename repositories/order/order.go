@@ -3,63 +3,167 @@ package repositories
 import (
 	"context"
 	"errors"
-	"fmt"
+	"order-service/constants"
 	"order-service/domain/dto"
 	"order-service/domain/models"
-	"strconv"
+	"strings"
 	"time"
 
 	errWrap "order-service/common/error"
+	"order-service/common/util"
 	errConstant "order-service/constants/error"
 	errOrder "order-service/constants/error/order"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository struct {
-	db *gorm.DB
+	db            *gorm.DB
+	codeGenerator ICodeGenerator
 }
 
 type IOrderRepository interface {
 	FindAllWithPagination(context.Context, *dto.OrderRequestParam) ([]models.Order, int64, error)
 	FindByUUID(context.Context, string) (*models.Order, error)
-	FindByUserID(context.Context, string) ([]models.Order, error)
+	FindByUUIDForUpdate(context.Context, *gorm.DB, string) (*models.Order, error)
+	FindAllWithCursor(context.Context, *dto.OrderRequestParam, *util.Cursor) ([]models.Order, bool, error)
+	FindExpired(context.Context, time.Time, time.Time, int) ([]models.Order, error)
+	FindIncompleteSaga(context.Context, time.Time, int) ([]models.Order, error)
+	FindMissingPaymentLinks(context.Context, uint, int) ([]models.Order, error)
+	GenerateCode(context.Context) (string, error)
 	Create(context.Context, *gorm.DB, *models.Order) (*models.Order, error)
 	Update(context.Context, *gorm.DB, *models.Order, uuid.UUID) error
 }
 
-func NewOrderRepository(db *gorm.DB) IOrderRepository {
-	return &OrderRepository{db: db}
+func NewOrderRepository(db *gorm.DB, codeGenerator ICodeGenerator) IOrderRepository {
+	return &OrderRepository{db: db, codeGenerator: codeGenerator}
 }
 
 func (o *OrderRepository) FindAllWithPagination(ctx context.Context, params *dto.OrderRequestParam) ([]models.Order, int64, error) {
 	var orders []models.Order
-	var sort string
 	var total int64
 
-	if params.SortColumn != nil {
-		sort = fmt.Sprintf("%s %s", *params.SortColumn, *params.SortOrder)
-	} else {
-		sort = "created_at desc"
-	}
-
 	limit := params.Limit
 	offset := (params.Page - 1) * params.Limit
 
-	err := o.db.WithContext(ctx).Limit(limit).Offset(offset).Order(sort).Find(&orders).Error
+	query := o.applyFilters(o.db.WithContext(ctx).Model(&models.Order{}), params)
+
+	err := query.Session(&gorm.Session{}).Limit(limit).Offset(offset).Clauses(o.orderBy(params)).Find(&orders).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
-	err = o.db.WithContext(ctx).Model(&models.Order{}).Count(&total).Error
+	err = query.Session(&gorm.Session{}).Count(&total).Error
 	if err != nil {
-		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return orders, total, nil
 }
 
+// applyFilters dipakai untuk query data dan count supaya total selalu sesuai dengan filter.
+func (o *OrderRepository) applyFilters(query *gorm.DB, params *dto.OrderRequestParam) *gorm.DB {
+	if params.UserID != nil {
+		query = query.Where("user_id = ?", *params.UserID)
+	}
+
+	if params.Status != nil {
+		query = query.Where("status = ?", params.Status.GetStatusInt())
+	}
+
+	if params.IsPaid != nil {
+		query = query.Where("is_paid = ?", *params.IsPaid)
+	}
+
+	if params.Code != nil && *params.Code != "" {
+		query = query.Where("code ILIKE ?", escapeLike(*params.Code)+"%")
+	}
+
+	if params.DateFrom != nil {
+		query = query.Where("date >= ?", *params.DateFrom)
+	}
+
+	if params.DateTo != nil {
+		query = query.Where("date < ?", params.DateTo.AddDate(0, 0, 1))
+	}
+
+	if params.PaidAtFrom != nil {
+		query = query.Where("paid_at >= ?", *params.PaidAtFrom)
+	}
+
+	if params.PaidAtTo != nil {
+		query = query.Where("paid_at < ?", params.PaidAtTo.AddDate(0, 0, 1))
+	}
+
+	if params.MinAmount != nil {
+		query = query.Where("amount >= ?", *params.MinAmount)
+	}
+
+	if params.MaxAmount != nil {
+		query = query.Where("amount <= ?", *params.MaxAmount)
+	}
+
+	return query
+}
+
+// orderBy menyusun ORDER BY hanya dari kolom yang ada di constants.OrderSortColumns,
+// ditutup dengan id supaya urutan data dengan nilai sama tetap stabil antar halaman.
+func (o *OrderRepository) orderBy(params *dto.OrderRequestParam) clause.OrderBy {
+	var columns, orders []string
+	if params.SortColumn != nil {
+		columns = constants.SplitSortValues(*params.SortColumn)
+	}
+	if params.SortOrder != nil {
+		orders = constants.SplitSortValues(*params.SortOrder)
+	}
+
+	if len(columns) == 0 {
+		columns = []string{"createdAt"}
+		if len(orders) == 0 {
+			orders = []string{string(constants.SortDesc)}
+		}
+	}
+
+	var (
+		orderBy = clause.OrderBy{}
+		used    = map[string]bool{}
+		desc    bool
+	)
+	for i, column := range columns {
+		dbColumn, ok := constants.OrderSortColumns[column]
+		if !ok || used[dbColumn] {
+			continue
+		}
+		used[dbColumn] = true
+
+		desc = false
+		if len(orders) == 1 {
+			desc = constants.SortOrder(strings.ToLower(orders[0])) == constants.SortDesc
+		} else if i < len(orders) {
+			desc = constants.SortOrder(strings.ToLower(orders[i])) == constants.SortDesc
+		}
+
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{
+			Column: clause.Column{Name: dbColumn},
+			Desc:   desc,
+		})
+	}
+
+	orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{
+		Column: clause.Column{Name: "id"},
+		Desc:   desc,
+	})
+
+	return orderBy
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
 func (o *OrderRepository) FindByUUID(ctx context.Context, orderUUID string) (*models.Order, error) {
 	var order models.Order
 
@@ -68,71 +172,149 @@ func (o *OrderRepository) FindByUUID(ctx context.Context, orderUUID string) (*mo
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errWrap.WrapError(errOrder.ErrOrderNotFound)
 		}
-		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return &order, nil
 }
 
-func (o *OrderRepository) FindByUserID(ctx context.Context, userID string) ([]models.Order, error) {
-	var orders []models.Order
+func (o *OrderRepository) FindByUUIDForUpdate(ctx context.Context, tx *gorm.DB, orderUUID string) (*models.Order, error) {
+	var order models.Order
 
-	err := o.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&orders).Error
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid = ?", orderUUID).
+		First(&order).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errWrap.WrapError(errOrder.ErrOrderNotFound)
 		}
-		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
-	return orders, nil
+	return &order, nil
 }
 
-func (o *OrderRepository) incrementCode(ctx context.Context) (*string, error) {
+// FindAllWithCursor membaca satu halaman order memakai keyset (created_at, id) dengan filter yang sama
+// seperti FindAllWithPagination. Satu baris tambahan diambil untuk menandai masih ada halaman berikutnya.
+func (o *OrderRepository) FindAllWithCursor(ctx context.Context, params *dto.OrderRequestParam, cursor *util.Cursor) ([]models.Order, bool, error) {
 	var (
-		order  *models.Order
-		result string
-		today  = time.Now().Format("20060102")
+		orders []models.Order
+		prev   = params.Direction == constants.CursorPrev
 	)
 
-	err := o.db.WithContext(ctx).Order("id desc").First(&order).Error
+	query := o.applyFilters(o.db.WithContext(ctx).Model(&models.Order{}), params)
+	if cursor != nil {
+		if prev {
+			query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+		} else {
+			query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+	}
+
+	err := query.Clauses(clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: "created_at"}, Desc: !prev},
+		{Column: clause.Column{Name: "id"}, Desc: !prev},
+	}}).Limit(params.Limit + 1).Find(&orders).Error
 	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		return nil, false, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	hasMore := len(orders) > params.Limit
+	if hasMore {
+		orders = orders[:params.Limit]
+	}
+
+	if prev {
+		for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+			orders[i], orders[j] = orders[j], orders[i]
 		}
 	}
 
-	if order.ID != 0 {
-		orderCode := order.Code
-		splitOrderName, _ := strconv.Atoi(orderCode[4:9])
-		code := splitOrderName + 1
-		result = fmt.Sprintf("ORD-%05d-%s", code, today)
-	} else {
-		result = fmt.Sprintf("ORD-%05d-%s", 1, today)
+	return orders, hasMore, nil
+}
+
+// FindExpired mencari order yang belum dibayar dan sudah lewat batas pembayaran.
+// Order lama yang belum punya expired_at memakai created_at sebelum legacyCutoff.
+func (o *OrderRepository) FindExpired(ctx context.Context, now, legacyCutoff time.Time, limit int) ([]models.Order, error) {
+	var orders []models.Order
+
+	err := o.db.WithContext(ctx).
+		Where("status IN ?", []constants.OrderStatus{constants.Pending, constants.PendingPayment}).
+		Where("(expired_at IS NOT NULL AND expired_at <= ?) OR (expired_at IS NULL AND created_at <= ?)", now, legacyCutoff).
+		Order("id asc").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return orders, nil
+}
+
+// FindIncompleteSaga mencari order pending yang sudah tercatat create_order tetapi belum sampai
+// attach_payment sebelum waktu before, misalnya karena proses mati di tengah Create.
+func (o *OrderRepository) FindIncompleteSaga(ctx context.Context, before time.Time, limit int) ([]models.Order, error) {
+	var orders []models.Order
+
+	err := o.db.WithContext(ctx).
+		Where("status = ?", constants.Pending).
+		Where(`EXISTS (SELECT 1 FROM order_saga_steps s WHERE s.order_id = orders.id
+			AND s.step = ? AND s.status = ? AND s.created_at <= ?)`,
+			constants.CreateOrderStep, constants.SagaStepCompleted, before).
+		Where(`NOT EXISTS (SELECT 1 FROM order_saga_steps s WHERE s.order_id = orders.id
+			AND s.step = ? AND s.status = ?)`,
+			constants.AttachPaymentStep, constants.SagaStepCompleted).
+		Order("id asc").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
-	return &result, nil
+	return orders, nil
 }
 
-func (o *OrderRepository) Create(ctx context.Context, tx *gorm.DB, param *models.Order) (*models.Order, error) {
-	code, err := o.incrementCode(ctx)
+// FindMissingPaymentLinks mencari order lama yang sudah punya payment tetapi belum menyimpan link,
+// urut berdasarkan id setelah afterID supaya backfill bisa berjalan per batch.
+func (o *OrderRepository) FindMissingPaymentLinks(ctx context.Context, afterID uint, limit int) ([]models.Order, error) {
+	var orders []models.Order
+
+	err := o.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Where("payment_id <> ?", uuid.Nil).
+		Where("payment_link IS NULL").
+		Order("id asc").
+		Limit(limit).
+		Find(&orders).Error
 	if err != nil {
-		return nil, err
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
+	return orders, nil
+}
+
+// GenerateCode dipanggil sebelum transaksi dibuka, karena generator memakai koneksi sendiri
+// dan tidak boleh menunggu koneksi kedua selama transaksi masih memegang koneksi dari pool.
+func (o *OrderRepository) GenerateCode(ctx context.Context) (string, error) {
+	return o.codeGenerator.Generate(ctx)
+}
+
+func (o *OrderRepository) Create(ctx context.Context, tx *gorm.DB, param *models.Order) (*models.Order, error) {
 	order := &models.Order{
-		UUID:   uuid.New(),
-		Code:   *code,
-		UserID: param.UserID,
-		Amount: param.Amount,
-		Date:   param.Date,
-		Status: param.Status,
-		IsPaid: param.IsPaid,
+		UUID:      uuid.New(),
+		Code:      param.Code,
+		UserID:    param.UserID,
+		Amount:    param.Amount,
+		Date:      param.Date,
+		Status:    param.Status,
+		IsPaid:    param.IsPaid,
+		ExpiredAt: param.ExpiredAt,
 	}
 
-	err = tx.WithContext(ctx).Create(order).Error
+	err := tx.WithContext(ctx).Create(order).Error
 	if err != nil {
-		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return order, nil
@@ -141,7 +323,7 @@ func (o *OrderRepository) Create(ctx context.Context, tx *gorm.DB, param *models
 func (o *OrderRepository) Update(ctx context.Context, tx *gorm.DB, param *models.Order, orderUUID uuid.UUID) error {
 	err := tx.WithContext(ctx).Model(&models.Order{}).Where("uuid = ?", orderUUID).Updates(param).Error
 	if err != nil {
-		return errWrap.WrapError(errConstant.ErrSQLError)
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return nil
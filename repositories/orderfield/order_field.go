@@ -2,8 +2,13 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"order-service/domain/models"
 
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+	errOrder "order-service/constants/error/order"
+
 	"gorm.io/gorm"
 )
 
@@ -13,7 +18,9 @@ type OrderFieldRepository struct {
 
 type IOrderFieldRepository interface {
 	FindByOrderID(context.Context, uint) ([]models.OrderField, error)
+	FindByOrderIDTx(context.Context, *gorm.DB, uint) ([]models.OrderField, error)
 	Create(context.Context, *gorm.DB, []models.OrderField) error
+	Deactivate(context.Context, *gorm.DB, uint) error
 }
 
 func NewOrderFieldRepository(db *gorm.DB) IOrderFieldRepository {
@@ -21,11 +28,16 @@ func NewOrderFieldRepository(db *gorm.DB) IOrderFieldRepository {
 }
 
 func (o *OrderFieldRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.OrderField, error) {
+	return o.FindByOrderIDTx(ctx, o.db, orderID)
+}
+
+// FindByOrderIDTx membaca lewat transaksi yang sedang berjalan supaya tidak mengambil koneksi kedua dari pool.
+func (o *OrderFieldRepository) FindByOrderIDTx(ctx context.Context, tx *gorm.DB, orderID uint) ([]models.OrderField, error) {
 	var orderFields []models.OrderField
 
-	err := o.db.WithContext(ctx).Where("order_id = ?", orderID).Find(&orderFields).Error
+	err := tx.WithContext(ctx).Where("order_id = ?", orderID).Find(&orderFields).Error
 	if err != nil {
-		return nil, err
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return orderFields, nil
@@ -34,7 +46,23 @@ func (o *OrderFieldRepository) FindByOrderID(ctx context.Context, orderID uint)
 func (o *OrderFieldRepository) Create(ctx context.Context, tx *gorm.DB, orderFields []models.OrderField) error {
 	err := tx.WithContext(ctx).Create(&orderFields).Error
 	if err != nil {
-		return err
+		// unique index idx_order_fields_active_schedule: jadwal sudah dipakai order lain yang masih aktif
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errWrap.WrapError(errOrder.ErrFieldAlreadyBooked.Wrap(err))
+		}
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+// Deactivate melepas jadwal order supaya bisa dipesan lagi oleh order lain.
+func (o *OrderFieldRepository) Deactivate(ctx context.Context, tx *gorm.DB, orderID uint) error {
+	err := tx.WithContext(ctx).Model(&models.OrderField{}).
+		Where("order_id = ? AND is_active = ?", orderID, true).
+		Update("is_active", false).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return nil
@@ -16,6 +16,7 @@ type OrderHistoryRepository struct {
 }
 
 type IOrderHistoryRepository interface {
+	FindByOrderID(context.Context, uint) ([]models.OrderHistory, error)
 	Create(context.Context, *gorm.DB, *dto.OrderHistoryRequest) error
 }
 
@@ -23,16 +24,28 @@ func NewOrderHistoryRepository(db *gorm.DB) IOrderHistoryRepository {
 	return &OrderHistoryRepository{db: db}
 }
 
+func (o *OrderHistoryRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.OrderHistory, error) {
+	var histories []models.OrderHistory
+
+	err := o.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at asc, id asc").Find(&histories).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return histories, nil
+}
+
 func (o *OrderHistoryRepository) Create(ctx context.Context, tx *gorm.DB, param *dto.OrderHistoryRequest) error {
 	orderHistory := models.OrderHistory{
 		OrderID: param.OrderID,
 		Status:  param.Status,
+		Remark:  param.Remark,
 	}
 
 	err := tx.WithContext(ctx).Create(&orderHistory).Error
 
 	if err != nil {
-		return errWrap.WrapError(errConstant.ErrSQLError)
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
 	}
 
 	return nil
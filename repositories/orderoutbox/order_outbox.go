@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"order-service/domain/models"
+	"time"
+
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderOutboxRepository struct {
+	db *gorm.DB
+}
+
+// outboxRelayLockKey adalah key advisory lock untuk relay, nilainya bebas asal tidak dipakai lock lain.
+const outboxRelayLockKey = 7_310_001
+
+type IOrderOutboxRepository interface {
+	Create(context.Context, *gorm.DB, *models.OrderOutbox) error
+	TryLockRelay(context.Context, *gorm.DB) (bool, error)
+	FindUnpublishedForUpdate(context.Context, *gorm.DB, int) ([]models.OrderOutbox, error)
+	MarkPublished(context.Context, *gorm.DB, uint) error
+	MarkFailed(context.Context, *gorm.DB, uint, error) error
+	MarkDead(context.Context, *gorm.DB, uint, error) error
+}
+
+func NewOrderOutboxRepository(db *gorm.DB) IOrderOutboxRepository {
+	return &OrderOutboxRepository{db: db}
+}
+
+func (o *OrderOutboxRepository) Create(ctx context.Context, tx *gorm.DB, param *models.OrderOutbox) error {
+	outbox := models.OrderOutbox{
+		OrderID:   param.OrderID,
+		OrderUUID: param.OrderUUID,
+		EventName: param.EventName,
+		Payload:   param.Payload,
+	}
+
+	err := tx.WithContext(ctx).Create(&outbox).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+// TryLockRelay mengambil advisory lock selama transaksi berjalan. Hanya satu relay (dari Serve
+// maupun OutboxRelay, di replica mana pun) yang boleh mempublikasikan supaya urutan event per order terjaga.
+func (o *OrderOutboxRepository) TryLockRelay(ctx context.Context, tx *gorm.DB) (bool, error) {
+	var locked bool
+
+	err := tx.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLockKey).Scan(&locked).Error
+	if err != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return locked, nil
+}
+
+// FindUnpublishedForUpdate mengunci batch event berikutnya, dipanggil setelah TryLockRelay berhasil.
+func (o *OrderOutboxRepository) FindUnpublishedForUpdate(ctx context.Context, tx *gorm.DB, limit int) ([]models.OrderOutbox, error) {
+	var outboxes []models.OrderOutbox
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("published_at IS NULL AND failed_at IS NULL").
+		Order("id asc").
+		Limit(limit).
+		Find(&outboxes).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return outboxes, nil
+}
+
+func (o *OrderOutboxRepository) MarkPublished(ctx context.Context, tx *gorm.DB, id uint) error {
+	now := time.Now()
+	err := tx.WithContext(ctx).Model(&models.OrderOutbox{}).Where("id = ?", id).Updates(map[string]any{
+		"published_at": &now,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   nil,
+	}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+func (o *OrderOutboxRepository) MarkFailed(ctx context.Context, tx *gorm.DB, id uint, cause error) error {
+	lastError := cause.Error()
+	err := tx.WithContext(ctx).Model(&models.OrderOutbox{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": &lastError,
+	}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+// MarkDead memarkir event yang tidak valid supaya tidak menahan event lain di antrean relay.
+func (o *OrderOutboxRepository) MarkDead(ctx context.Context, tx *gorm.DB, id uint, cause error) error {
+	now := time.Now()
+	lastError := cause.Error()
+	err := tx.WithContext(ctx).Model(&models.OrderOutbox{}).Where("id = ?", id).Updates(map[string]any{
+		"failed_at":  &now,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": &lastError,
+	}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"order-service/domain/models"
+
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+
+	"gorm.io/gorm"
+)
+
+type OrderSagaStepRepository struct {
+	db *gorm.DB
+}
+
+type IOrderSagaStepRepository interface {
+	FindByOrderID(context.Context, uint) ([]models.OrderSagaStep, error)
+	Create(context.Context, *gorm.DB, *models.OrderSagaStep) error
+}
+
+func NewOrderSagaStepRepository(db *gorm.DB) IOrderSagaStepRepository {
+	return &OrderSagaStepRepository{db: db}
+}
+
+func (o *OrderSagaStepRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.OrderSagaStep, error) {
+	var steps []models.OrderSagaStep
+
+	err := o.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id asc").Find(&steps).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return steps, nil
+}
+
+func (o *OrderSagaStepRepository) Create(ctx context.Context, tx *gorm.DB, param *models.OrderSagaStep) error {
+	step := models.OrderSagaStep{
+		OrderID:   param.OrderID,
+		Step:      param.Step,
+		Status:    param.Status,
+		Error:     param.Error,
+		PaymentID: param.PaymentID,
+	}
+
+	err := tx.WithContext(ctx).Create(&step).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"order-service/constants"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const legacyTable = "field_schedule_tasks"
+
+// MigrateLegacyTasks memindahkan antrean field_schedule_tasks lama ke order_tasks sebelum AutoMigrate,
+// supaya task yang belum selesai tetap dijalankan oleh worker.
+func MigrateLegacyTasks(ctx context.Context, db *gorm.DB) error {
+	migrator := db.WithContext(ctx).Migrator()
+	if !migrator.HasTable(legacyTable) || migrator.HasTable("order_tasks") {
+		return nil
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+
+		err := migrator.RenameTable(legacyTable, "order_tasks")
+		if err != nil {
+			return err
+		}
+
+		err = migrator.RenameColumn("order_tasks", "field_schedule_ids", "payload")
+		if err != nil {
+			return err
+		}
+
+		if migrator.HasColumn("order_tasks", "payment_id") {
+			err = tx.Exec("UPDATE order_tasks SET payload = jsonb_build_object('payment_id', payment_id) WHERE action = ?",
+				constants.VoidPaymentAction).Error
+			if err != nil {
+				return err
+			}
+
+			err = migrator.DropColumn("order_tasks", "payment_id")
+			if err != nil {
+				return err
+			}
+		}
+
+		logrus.Infof("[MigrateLegacyTasks] renamed %s to order_tasks", legacyTable)
+		return nil
+	})
+}
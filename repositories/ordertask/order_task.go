@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"order-service/domain/models"
+	"time"
+
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderTaskRepository struct {
+	db *gorm.DB
+}
+
+type IOrderTaskRepository interface {
+	FindDueForUpdate(context.Context, *gorm.DB, time.Time, int) ([]models.OrderTask, error)
+	Claim(context.Context, *gorm.DB, []uint, time.Time) error
+	Create(context.Context, *gorm.DB, *models.OrderTask) (*models.OrderTask, error)
+	MarkCompleted(context.Context, *gorm.DB, uint) error
+	MarkFailed(context.Context, *gorm.DB, uint, error, time.Time) error
+}
+
+func NewOrderTaskRepository(db *gorm.DB) IOrderTaskRepository {
+	return &OrderTaskRepository{db: db}
+}
+
+func (r *OrderTaskRepository) FindDueForUpdate(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]models.OrderTask, error) {
+	var tasks []models.OrderTask
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("completed_at IS NULL AND next_attempt_at <= ?", now).
+		Order("id asc").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return tasks, nil
+}
+
+// Claim memundurkan next_attempt_at task yang sedang dikerjakan supaya worker lain tidak mengambilnya
+// selama lease, dan task otomatis diambil lagi kalau worker mati sebelum selesai.
+func (r *OrderTaskRepository) Claim(ctx context.Context, tx *gorm.DB, ids []uint, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := tx.WithContext(ctx).Model(&models.OrderTask{}).Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+func (r *OrderTaskRepository) Create(ctx context.Context, tx *gorm.DB, param *models.OrderTask) (*models.OrderTask, error) {
+	task := &models.OrderTask{
+		OrderID:       param.OrderID,
+		Action:        param.Action,
+		Payload:       param.Payload,
+		NextAttemptAt: param.NextAttemptAt,
+	}
+
+	err := tx.WithContext(ctx).Create(task).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return task, nil
+}
+
+func (r *OrderTaskRepository) MarkCompleted(ctx context.Context, tx *gorm.DB, id uint) error {
+	now := time.Now()
+	err := tx.WithContext(ctx).Model(&models.OrderTask{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"completed_at": &now,
+		"last_error":   nil,
+	}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
+
+func (r *OrderTaskRepository) MarkFailed(ctx context.Context, tx *gorm.DB, id uint, cause error, nextAttemptAt time.Time) error {
+	lastError := cause.Error()
+	err := tx.WithContext(ctx).Model(&models.OrderTask{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      &lastError,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"order-service/domain/models"
+
+	errWrap "order-service/common/error"
+	errConstant "order-service/constants/error"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProcessedEventRepository struct {
+	db *gorm.DB
+}
+
+type IProcessedEventRepository interface {
+	Exists(context.Context, string) (bool, error)
+	Create(context.Context, *gorm.DB, *models.ProcessedEvent) (bool, error)
+}
+
+func NewProcessedEventRepository(db *gorm.DB) IProcessedEventRepository {
+	return &ProcessedEventRepository{db: db}
+}
+
+func (p *ProcessedEventRepository) Exists(ctx context.Context, eventKey string) (bool, error) {
+	var count int64
+
+	err := p.db.WithContext(ctx).Model(&models.ProcessedEvent{}).Where("event_key = ?", eventKey).Count(&count).Error
+	if err != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError.Wrap(err))
+	}
+
+	return count > 0, nil
+}
+
+// Create mengembalikan false jika event dengan key yang sama sudah pernah diproses.
+func (p *ProcessedEventRepository) Create(ctx context.Context, tx *gorm.DB, param *models.ProcessedEvent) (bool, error) {
+	event := models.ProcessedEvent{
+		EventKey: param.EventKey,
+		OrderID:  param.OrderID,
+	}
+
+	result := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_key"}}, DoNothing: true}).
+		Create(&event)
+	if result.Error != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError.Wrap(result.Error))
+	}
+
+	return result.RowsAffected > 0, nil
+}
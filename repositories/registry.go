@@ -1,9 +1,14 @@
 package repositories
 
 import (
+	"order-service/config"
 	orderRepo "order-service/repositories/order"
 	orderFieldRepo "order-service/repositories/orderfield"
 	orderHistoryRepo "order-service/repositories/orderhistory"
+	orderOutboxRepo "order-service/repositories/orderoutbox"
+	orderSagaStepRepo "order-service/repositories/ordersagastep"
+	orderTaskRepo "order-service/repositories/ordertask"
+	processedEventRepo "order-service/repositories/processedevent"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +21,10 @@ type IRepositoryRegistry interface {
 	GetOrder() orderRepo.IOrderRepository
 	GetOrderField() orderFieldRepo.IOrderFieldRepository
 	GetOrderHistory() orderHistoryRepo.IOrderHistoryRepository
+	GetOrderOutbox() orderOutboxRepo.IOrderOutboxRepository
+	GetOrderSagaStep() orderSagaStepRepo.IOrderSagaStepRepository
+	GetOrderTask() orderTaskRepo.IOrderTaskRepository
+	GetProcessedEvent() processedEventRepo.IProcessedEventRepository
 	GetTx() *gorm.DB
 }
 
@@ -24,7 +33,11 @@ func NewRepositoryRegistry(db *gorm.DB) IRepositoryRegistry {
 }
 
 func (r *Registry) GetOrder() orderRepo.IOrderRepository {
-	return orderRepo.NewOrderRepository(r.db)
+	return orderRepo.NewOrderRepository(r.db, orderRepo.NewSequenceCodeGenerator(
+		r.db,
+		config.Config.Order.CodeFormat,
+		config.Config.Order.CodeResetDaily,
+	))
 }
 
 func (r *Registry) GetOrderField() orderFieldRepo.IOrderFieldRepository {
@@ -35,6 +48,25 @@ func (r *Registry) GetOrderHistory() orderHistoryRepo.IOrderHistoryRepository {
 	return orderHistoryRepo.NewOrderHistoryRepository(r.db)
 }
 
+func (r *Registry) GetOrderOutbox() orderOutboxRepo.IOrderOutboxRepository {
+	return orderOutboxRepo.NewOrderOutboxRepository(r.db)
+}
+
+func (r *Registry) GetOrderSagaStep() orderSagaStepRepo.IOrderSagaStepRepository {
+	return orderSagaStepRepo.NewOrderSagaStepRepository(r.db)
+}
+
+func (r *Registry) GetOrderTask() orderTaskRepo.IOrderTaskRepository {
+	return orderTaskRepo.NewOrderTaskRepository(r.db)
+}
+
+func (r *Registry) GetProcessedEvent() processedEventRepo.IProcessedEventRepository {
+	return processedEventRepo.NewProcessedEventRepository(r.db)
+}
+
+// GetTx mengembalikan koneksi DB bersama untuk dipakai dengan Transaction(...).
+// Sebelumnya memanggil r.db.Begin() sehingga setiap pemanggilan membuka transaksi yang tidak pernah
+// di-commit, dan Transaction di atasnya berjalan sebagai savepoint di dalam transaksi yang bocor.
 func (r *Registry) GetTx() *gorm.DB {
-	return r.db.Begin()
+	return r.db
 }
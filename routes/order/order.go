@@ -35,4 +35,5 @@ func (r *OrderRoutes) Run() {
 	group.GET("/:uuid", middlewares.CheckRole([]string{constants.Admin, constants.Customer}, r.client), r.controller.GetOrder().GetByUUID)
 	group.GET("/user", middlewares.CheckRole([]string{constants.Customer}, r.client), r.controller.GetOrder().GetOrderByUserID)
 	group.POST("", middlewares.CheckRole([]string{constants.Customer}, r.client), r.controller.GetOrder().Create)
+	group.POST("/:uuid/cancel", middlewares.CheckRole([]string{constants.Admin, constants.Customer}, r.client), r.controller.GetOrder().Cancel)
 }
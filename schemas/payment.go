@@ -0,0 +1,55 @@
+package schemas
+
+import (
+	"encoding/json"
+	errConstant "order-service/constants/error"
+	"order-service/domain/dto"
+	"time"
+)
+
+// DecodePayment memvalidasi payment callback terhadap schema sesuai event.version lalu
+// mengubahnya ke dto.PaymentContent. Message tanpa version dianggap v1.
+func DecodePayment(value []byte) (*dto.PaymentContent, error) {
+	var envelope struct {
+		Event dto.KafkaEvent `json:"event"`
+	}
+
+	err := json.Unmarshal(value, &envelope)
+	if err != nil {
+		return nil, errConstant.ErrInvalidMessage.Wrap(err)
+	}
+
+	version := envelope.Event.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	err = Validate(Payment, version, value)
+	if err != nil {
+		return nil, errConstant.ErrInvalidMessage.Wrap(err)
+	}
+
+	var body dto.PaymentContent
+	switch version {
+	case V2:
+		var bodyV2 dto.PaymentContentV2
+		err = json.Unmarshal(value, &bodyV2)
+		body = bodyV2.ToV1()
+	default:
+		err = json.Unmarshal(value, &body)
+	}
+	if err != nil {
+		return nil, errConstant.ErrInvalidMessage.Wrap(err)
+	}
+
+	body.Event.Version = version
+	body.Body.Data.Sequence = body.Metadata.Sequence
+	sentAt, err := time.Parse(time.RFC3339, body.Metadata.SendingAt)
+	if err == nil {
+		// kolom timestamp membuang offset, simpan dalam UTC supaya perbandingan tetap pada instant yang sama
+		sentAt = sentAt.UTC()
+		body.Body.Data.SentAt = &sentAt
+	}
+
+	return &body, nil
+}
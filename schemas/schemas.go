@@ -0,0 +1,124 @@
+// Package schemas menyimpan JSON Schema untuk message Kafka yang dikonsumsi dan dipublikasikan
+// order-service. File schema di-embed supaya validasi tidak bergantung pada working directory.
+package schemas
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+type Name string
+
+const (
+	Payment Name = "payment"
+	Order   Name = "order"
+
+	V1 = "v1"
+	V2 = "v2"
+
+	// DefaultVersion dipakai untuk message lama yang belum mengirim event.version.
+	DefaultVersion = V1
+)
+
+// baseURL sama dengan prefix $id di setiap file schema.
+const baseURL = "https://order-service/schemas/"
+
+//go:embed payment/*.json order/*.json
+var files embed.FS
+
+var (
+	compileOnce sync.Once
+	compiled    map[string]*jsonschema.Schema
+	compileErr  error
+)
+
+// ErrUnsupportedVersion dikembalikan jika tidak ada file schema untuk versi yang diminta.
+var ErrUnsupportedVersion = errors.New("unsupported schema version")
+
+func location(name Name, version string) string {
+	return path.Join(string(name), version+".json")
+}
+
+func compile() {
+	compiled = map[string]*jsonschema.Schema{}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	entries := []string{}
+	for _, dir := range []Name{Payment, Order} {
+		names, err := files.ReadDir(string(dir))
+		if err != nil {
+			compileErr = err
+			return
+		}
+		for _, entry := range names {
+			entries = append(entries, path.Join(string(dir), entry.Name()))
+		}
+	}
+
+	for _, file := range entries {
+		content, err := files.ReadFile(file)
+		if err != nil {
+			compileErr = err
+			return
+		}
+
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(content))
+		if err != nil {
+			compileErr = fmt.Errorf("%s: %w", file, err)
+			return
+		}
+
+		err = compiler.AddResource(baseURL+file, doc)
+		if err != nil {
+			compileErr = fmt.Errorf("%s: %w", file, err)
+			return
+		}
+	}
+
+	for _, file := range entries {
+		schema, err := compiler.Compile(baseURL + file)
+		if err != nil {
+			compileErr = fmt.Errorf("%s: %w", file, err)
+			return
+		}
+		compiled[file] = schema
+	}
+}
+
+// Validate memeriksa message terhadap schema name/version. Error yang dikembalikan
+// menyebutkan field mana yang tidak sesuai.
+func Validate(name Name, version string, message []byte) error {
+	compileOnce.Do(compile)
+	if compileErr != nil {
+		return compileErr
+	}
+
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	schema, ok := compiled[location(name, version)]
+	if !ok {
+		return fmt.Errorf("%w: %s %s", ErrUnsupportedVersion, name, version)
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("invalid %s %s message: %w", name, version, err)
+	}
+
+	err = schema.Validate(instance)
+	if err != nil {
+		return fmt.Errorf("invalid %s %s message: %w", name, version, err)
+	}
+
+	return nil
+}
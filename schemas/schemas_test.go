@@ -0,0 +1,123 @@
+package schemas
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"order-service/constants"
+	errConstant "order-service/constants/error"
+
+	"github.com/google/uuid"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "payment", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return content
+}
+
+func TestValidatePayment(t *testing.T) {
+	tests := []struct {
+		fixture string
+		version string
+		valid   bool
+	}{
+		{fixture: "v1/valid.json", version: V1, valid: true},
+		{fixture: "v1/missing_order_id.json", version: V1},
+		{fixture: "v1/nil_order_id.json", version: V1},
+		{fixture: "v1/unknown_status.json", version: V1},
+		{fixture: "v2/valid.json", version: V2, valid: true},
+		{fixture: "v2/missing_order_id.json", version: V2},
+		{fixture: "v2/nil_order_id.json", version: V2},
+		{fixture: "v2/unknown_status.json", version: V2},
+		// message v2 tidak sesuai dengan schema v1 dan sebaliknya
+		{fixture: "v2/valid.json", version: V1},
+		{fixture: "v1/valid.json", version: V2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+tt.fixture, func(t *testing.T) {
+			err := Validate(Payment, tt.version, readFixture(t, tt.fixture))
+			if tt.valid && err != nil {
+				t.Fatalf("expected valid message, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
+
+func TestValidateUnknownVersion(t *testing.T) {
+	err := Validate(Payment, "v9", readFixture(t, "unknown_version.json"))
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("err = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
+
+func TestDecodePayment(t *testing.T) {
+	orderID := uuid.MustParse("5f0c7f5e-0a52-4d7a-9a38-1c7f1f0a6b11")
+	paymentID := uuid.MustParse("8d2b6c1e-3f44-4b8e-9e2a-6a0f3c9d7e22")
+	sentAt := time.Date(2025, 1, 1, 3, 3, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		fixture string
+		version string
+	}{
+		{fixture: "v1/valid.json", version: V1},
+		{fixture: "v2/valid.json", version: V2},
+	} {
+		t.Run(tt.fixture, func(t *testing.T) {
+			content, err := DecodePayment(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("DecodePayment: %v", err)
+			}
+
+			data := content.Body.Data
+			if content.Event.Version != tt.version {
+				t.Errorf("version = %q, want %q", content.Event.Version, tt.version)
+			}
+			if data.OrderID != orderID || data.PaymentID != paymentID {
+				t.Errorf("ids = %s/%s, want %s/%s", data.OrderID, data.PaymentID, orderID, paymentID)
+			}
+			if data.Status != constants.SettlementPaymentStatus {
+				t.Errorf("status = %q, want %q", data.Status, constants.SettlementPaymentStatus)
+			}
+			if data.PaidAt == nil || data.PaymentLink == nil || data.InvoiceLink == nil {
+				t.Errorf("paid_at and links must be decoded, got %+v", data)
+			}
+			if data.Sequence == nil || *data.Sequence != 2 {
+				t.Errorf("sequence = %v, want 2", data.Sequence)
+			}
+			if data.SentAt == nil || !data.SentAt.Equal(sentAt) || data.SentAt.Location() != time.UTC {
+				t.Errorf("sent at = %v, want %v in UTC", data.SentAt, sentAt)
+			}
+		})
+	}
+}
+
+func TestDecodePaymentInvalid(t *testing.T) {
+	for _, fixture := range []string{
+		"v1/missing_order_id.json",
+		"v1/nil_order_id.json",
+		"v1/unknown_status.json",
+		"v2/missing_order_id.json",
+		"v2/nil_order_id.json",
+		"v2/unknown_status.json",
+		"unknown_version.json",
+	} {
+		t.Run(fixture, func(t *testing.T) {
+			_, err := DecodePayment(readFixture(t, fixture))
+			if !errors.Is(err, errConstant.ErrInvalidMessage) {
+				t.Fatalf("err = %v, want %v", err, errConstant.ErrInvalidMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	clientUser "order-service/clients/user"
+	"order-service/constants"
+	errConstant "order-service/constants/error"
+	errOrder "order-service/constants/error/order"
+	"order-service/domain/models"
+)
+
+func (o *OrderService) currentUser(ctx context.Context) (*clientUser.UserData, error) {
+	user, ok := ctx.Value(constants.User).(*clientUser.UserData)
+	if !ok || user == nil {
+		return nil, errConstant.ErrUnauthorized
+	}
+
+	return user, nil
+}
+
+func (o *OrderService) isAdmin(user *clientUser.UserData) bool {
+	return user.Role == constants.Admin
+}
+
+// authorizeOrder memastikan customer hanya bisa mengakses order miliknya sendiri.
+// Order milik orang lain dianggap tidak ada supaya keberadaannya tidak bocor.
+func (o *OrderService) authorizeOrder(user *clientUser.UserData, order *models.Order) error {
+	if o.isAdmin(user) || order.UserID == user.UUID {
+		return nil
+	}
+
+	return errOrder.ErrOrderNotFound
+}
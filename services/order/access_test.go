@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	clientUser "order-service/clients/user"
+	"order-service/constants"
+	errOrder "order-service/constants/error/order"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type accessFixture struct {
+	service    *OrderService
+	repository *fakeOrderRepository
+	alice      *clientUser.UserData
+	bob        *clientUser.UserData
+	admin      *clientUser.UserData
+	aliceOrder models.Order
+	bobOrder   models.Order
+}
+
+func newAccessFixture() *accessFixture {
+	f := &accessFixture{
+		alice: newFakeUser(constants.Customer),
+		bob:   newFakeUser(constants.Customer),
+		admin: newFakeUser(constants.Admin),
+	}
+	f.aliceOrder = newFakeOrder(1, f.alice.UUID)
+	f.bobOrder = newFakeOrder(2, f.bob.UUID)
+
+	f.repository = &fakeOrderRepository{orders: []models.Order{f.aliceOrder, f.bobOrder}}
+	f.service = &OrderService{
+		repository: &fakeRepositoryRegistry{order: f.repository},
+		client: &fakeClientRegistry{user: &fakeUserClient{users: map[uuid.UUID]*clientUser.UserData{
+			f.alice.UUID: f.alice,
+			f.bob.UUID:   f.bob,
+		}}},
+	}
+
+	return f
+}
+
+func orderUUIDs(data any) []uuid.UUID {
+	responses, _ := data.([]dto.OrderResponse)
+	result := make([]uuid.UUID, 0, len(responses))
+	for _, response := range responses {
+		result = append(result, response.UUID)
+	}
+	return result
+}
+
+func TestGetByUUIDOwnOrder(t *testing.T) {
+	f := newAccessFixture()
+
+	response, err := f.service.GetByUUID(withUser(f.alice), f.aliceOrder.UUID.String(), nil)
+	if err != nil {
+		t.Fatalf("GetByUUID: %v", err)
+	}
+	if response.UUID != f.aliceOrder.UUID || response.UserName != f.alice.Name {
+		t.Fatalf("got order %s for %q, want %s for %q", response.UUID, response.UserName, f.aliceOrder.UUID, f.alice.Name)
+	}
+}
+
+func TestGetByUUIDOtherCustomerOrder(t *testing.T) {
+	f := newAccessFixture()
+
+	_, err := f.service.GetByUUID(withUser(f.alice), f.bobOrder.UUID.String(), nil)
+	if !errors.Is(err, errOrder.ErrOrderNotFound) {
+		t.Fatalf("err = %v, want %v", err, errOrder.ErrOrderNotFound)
+	}
+}
+
+func TestGetByUUIDAdminSeesAnyOrder(t *testing.T) {
+	f := newAccessFixture()
+
+	for _, order := range []models.Order{f.aliceOrder, f.bobOrder} {
+		response, err := f.service.GetByUUID(withUser(f.admin), order.UUID.String(), nil)
+		if err != nil {
+			t.Fatalf("GetByUUID %s: %v", order.UUID, err)
+		}
+		if response.UUID != order.UUID {
+			t.Fatalf("got order %s, want %s", response.UUID, order.UUID)
+		}
+	}
+}
+
+func TestGetAllWithPaginationScopesCustomer(t *testing.T) {
+	f := newAccessFixture()
+
+	result, err := f.service.GetAllWithPagination(withUser(f.alice), &dto.OrderRequestParam{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAllWithPagination: %v", err)
+	}
+
+	got := orderUUIDs(result.Data)
+	if len(got) != 1 || got[0] != f.aliceOrder.UUID {
+		t.Fatalf("got orders %v, want only %s", got, f.aliceOrder.UUID)
+	}
+}
+
+func TestGetAllWithPaginationAdminSeesAll(t *testing.T) {
+	f := newAccessFixture()
+
+	result, err := f.service.GetAllWithPagination(withUser(f.admin), &dto.OrderRequestParam{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAllWithPagination: %v", err)
+	}
+
+	if got := orderUUIDs(result.Data); len(got) != 2 {
+		t.Fatalf("got %d orders, want 2", len(got))
+	}
+	if f.repository.lastParams.UserID != nil {
+		t.Fatalf("admin query scoped to user %s", f.repository.lastParams.UserID)
+	}
+}
+
+func TestGetAllWithPaginationAdminFiltersByUser(t *testing.T) {
+	f := newAccessFixture()
+	userUUID := f.bob.UUID.String()
+
+	result, err := f.service.GetAllWithPagination(withUser(f.admin), &dto.OrderRequestParam{Page: 1, Limit: 10, UserUUID: &userUUID})
+	if err != nil {
+		t.Fatalf("GetAllWithPagination: %v", err)
+	}
+
+	got := orderUUIDs(result.Data)
+	if len(got) != 1 || got[0] != f.bobOrder.UUID {
+		t.Fatalf("got orders %v, want only %s", got, f.bobOrder.UUID)
+	}
+}
+
+func TestCustomerUserUUIDFilterIgnored(t *testing.T) {
+	f := newAccessFixture()
+	userUUID := f.bob.UUID.String()
+
+	tests := []struct {
+		name string
+		list func(*dto.OrderRequestParam) (any, error)
+	}{
+		{name: "offset", list: func(param *dto.OrderRequestParam) (any, error) {
+			result, err := f.service.GetAllWithPagination(withUser(f.alice), param)
+			if err != nil {
+				return nil, err
+			}
+			return result.Data, nil
+		}},
+		{name: "cursor", list: func(param *dto.OrderRequestParam) (any, error) {
+			param.Pagination = constants.PaginationCursor
+			result, err := f.service.GetAllWithCursor(withUser(f.alice), param)
+			if err != nil {
+				return nil, err
+			}
+			return result.Data, nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := &dto.OrderRequestParam{Page: 1, Limit: 10, UserUUID: &userUUID}
+			data, err := tt.list(param)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+
+			if param.UserID == nil || *param.UserID != f.alice.UUID {
+				t.Fatalf("query scoped to %v, want %s", param.UserID, f.alice.UUID)
+			}
+			got := orderUUIDs(data)
+			if len(got) != 1 || got[0] != f.aliceOrder.UUID {
+				t.Fatalf("got orders %v, want only %s", got, f.aliceOrder.UUID)
+			}
+		})
+	}
+}
+
+func TestListWithoutUserUnauthorized(t *testing.T) {
+	f := newAccessFixture()
+
+	_, err := f.service.GetAllWithPagination(withUser(nil), &dto.OrderRequestParam{Page: 1, Limit: 10})
+	if err == nil {
+		t.Fatal("expected an error without a logged in user")
+	}
+}
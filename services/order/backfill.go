@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"order-service/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultBackfillBatchSize = 100
+
+// BackfillPaymentLinks mengisi payment_link dan invoice_link order lama dari payment service,
+// supaya riwayat order tidak menampilkan link kosong untuk order yang dibuat sebelum link disimpan.
+func (o *OrderService) BackfillPaymentLinks(ctx context.Context, batchSize int) (int, error) {
+	var (
+		afterID uint
+		updated int
+	)
+
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	for {
+		orders, err := o.repository.GetOrder().FindMissingPaymentLinks(ctx, afterID, batchSize)
+		if err != nil {
+			return updated, err
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return updated, ctx.Err()
+			}
+
+			payment, err := o.client.GetPayment().GetPaymentByUUID(ctx, order.PaymentID)
+			if err != nil {
+				logrus.Errorf("[OrderService-BackfillPaymentLinks] failed to get payment %s for order %s: %v", order.PaymentID, order.UUID, err)
+				continue
+			}
+
+			if payment.PaymentLink == "" {
+				logrus.Warnf("[OrderService-BackfillPaymentLinks] payment %s for order %s has no payment link", order.PaymentID, order.UUID)
+				continue
+			}
+
+			err = o.repository.GetOrder().Update(ctx, o.repository.GetTx(), &models.Order{
+				PaymentLink: &payment.PaymentLink,
+				InvoiceLink: payment.InvoiceLink,
+			}, order.UUID)
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+
+		if len(orders) < batchSize {
+			return updated, nil
+		}
+		afterID = orders[len(orders)-1].ID
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	clientField "order-service/clients/field"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// getOrderFields mengambil jadwal lapangan milik order dan melengkapinya dengan data dari field service.
+func (o *OrderService) getOrderFields(ctx context.Context, orderID uint) ([]dto.OrderFieldResponse, error) {
+	orderFields, err := o.repository.GetOrderField().FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]dto.OrderFieldResponse, 0, len(orderFields))
+	for _, orderField := range orderFields {
+		// order lama belum punya snapshot, datanya diambil dari field service
+		if orderField.FieldName == "" {
+			field, err := o.client.GetField().GetFieldByUUID(ctx, orderField.FieldScheduleID)
+			if err != nil {
+				return nil, err
+			}
+
+			snapshot := newOrderFieldSnapshot(field)
+			snapshot.IsActive = orderField.IsActive
+			orderField = snapshot
+		}
+
+		fields = append(fields, dto.OrderFieldResponse{
+			FieldScheduleID: orderField.FieldScheduleID,
+			FieldID:         orderField.FieldID,
+			FieldName:       orderField.FieldName,
+			Date:            orderField.Date,
+			StartTime:       orderField.StartTime,
+			EndTime:         orderField.EndTime,
+			Price:           orderField.UnitPrice,
+			IsActive:        orderField.IsActive,
+		})
+	}
+
+	return fields, nil
+}
+
+// newOrderFieldSnapshot menyalin data jadwal dari field service ke order_fields.
+func newOrderFieldSnapshot(field *clientField.FieldData) models.OrderField {
+	orderField := models.OrderField{
+		FieldScheduleID: field.UUID,
+		FieldName:       field.FieldName,
+		Date:            field.Date,
+		StartTime:       field.StartTime,
+		EndTime:         field.EndTime,
+		UnitPrice:       field.PricePerHour,
+		IsActive:        true,
+	}
+
+	if field.FieldID != uuid.Nil {
+		fieldID := field.FieldID
+		orderField.FieldID = &fieldID
+	}
+
+	return orderField
+}
+
+func (o *OrderService) getOrderHistories(ctx context.Context, orderID uint) ([]dto.OrderHistoryResponse, error) {
+	orderHistories, err := o.repository.GetOrderHistory().FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]dto.OrderHistoryResponse, 0, len(orderHistories))
+	for _, history := range orderHistories {
+		histories = append(histories, dto.OrderHistoryResponse{
+			Status:    history.Status,
+			Remark:    history.Remark,
+			CreatedAt: history.CreatedAt,
+		})
+	}
+
+	return histories, nil
+}
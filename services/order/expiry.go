@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"order-service/config"
+	"order-service/constants"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultExpirySweepBatchSize = 100
+
+// ExpireStaleOrders meng-expire order yang belum dibayar setelah batas pembayaran lewat,
+// untuk berjaga-jaga kalau callback expired dari payment service tidak pernah datang.
+func (o *OrderService) ExpireStaleOrders(ctx context.Context) (int, error) {
+	var (
+		now       = time.Now()
+		batchSize = config.Config.Order.ExpirySweepBatchSize
+		expired   int
+	)
+
+	if batchSize <= 0 {
+		batchSize = defaultExpirySweepBatchSize
+	}
+
+	// order baru di-expire setelah grace period lewat, supaya settlement yang terlambat masih diterima
+	cutoff := now.Add(-config.Config.Order.ExpirySweepGrace())
+	orders, err := o.repository.GetOrder().FindExpired(ctx, cutoff, cutoff.Add(-config.Config.Order.PaymentExpiry()), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range orders {
+		ok, err := o.expireOrder(ctx, &orders[i])
+		if err != nil {
+			logrus.Errorf("[OrderService-ExpireStaleOrders] failed to expire order %s: %v", orders[i].UUID, err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+func (o *OrderService) expireOrder(ctx context.Context, stale *models.Order) (bool, error) {
+	var (
+		order   *models.Order
+		task    *models.OrderTask
+		skipped bool
+	)
+
+	err := o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		order, txErr = o.repository.GetOrder().FindByUUIDForUpdate(ctx, tx, stale.UUID.String())
+		if txErr != nil {
+			return txErr
+		}
+
+		// status bisa saja sudah berubah oleh callback payment sejak order dibaca
+		if order.Status != constants.Pending && order.Status != constants.PendingPayment {
+			skipped = true
+			return nil
+		}
+
+		txErr = o.validateTransition(order, constants.Expired)
+		if txErr != nil {
+			return txErr
+		}
+
+		body := &models.Order{Status: constants.Expired}
+		txErr = o.repository.GetOrder().Update(ctx, tx, body, order.UUID)
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderHistory().Create(ctx, tx, &dto.OrderHistoryRequest{
+			Status:  constants.Expired.GetStatusString(),
+			OrderID: order.ID,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderField().Deactivate(ctx, tx, order.ID)
+		if txErr != nil {
+			return txErr
+		}
+
+		task, txErr = o.queueFieldScheduleTask(ctx, tx, order, constants.ReleaseFieldScheduleAction)
+		if txErr != nil {
+			return txErr
+		}
+
+		o.applyOrderUpdate(order, body)
+		return o.enqueueEvent(ctx, tx, order)
+	})
+	if err != nil || skipped {
+		return false, err
+	}
+
+	logrus.Infof("[OrderService-expireOrder] order %s expired", order.UUID)
+	o.dispatchOrderTask(ctx, task)
+
+	return true, nil
+}
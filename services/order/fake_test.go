@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"order-service/clients"
+	clientField "order-service/clients/field"
+	clientPayment "order-service/clients/payment"
+	clientUser "order-service/clients/user"
+	"order-service/common/util"
+	"order-service/constants"
+	errOrder "order-service/constants/error/order"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"order-service/repositories"
+	orderRepo "order-service/repositories/order"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeRepositoryRegistry hanya menyediakan repository yang dipakai test, method lain dari
+// interface yang di-embed akan panic jika terpanggil.
+type fakeRepositoryRegistry struct {
+	repositories.IRepositoryRegistry
+	order *fakeOrderRepository
+}
+
+func (f *fakeRepositoryRegistry) GetOrder() orderRepo.IOrderRepository {
+	return f.order
+}
+
+type fakeOrderRepository struct {
+	orderRepo.IOrderRepository
+	orders     []models.Order
+	lastParams *dto.OrderRequestParam
+}
+
+func (f *fakeOrderRepository) scoped(params *dto.OrderRequestParam) []models.Order {
+	f.lastParams = params
+
+	result := []models.Order{}
+	for _, order := range f.orders {
+		if params.UserID != nil && order.UserID != *params.UserID {
+			continue
+		}
+		result = append(result, order)
+	}
+	return result
+}
+
+func (f *fakeOrderRepository) FindAllWithPagination(_ context.Context, params *dto.OrderRequestParam) ([]models.Order, int64, error) {
+	orders := f.scoped(params)
+	return orders, int64(len(orders)), nil
+}
+
+func (f *fakeOrderRepository) FindAllWithCursor(_ context.Context, params *dto.OrderRequestParam, _ *util.Cursor) ([]models.Order, bool, error) {
+	return f.scoped(params), false, nil
+}
+
+func (f *fakeOrderRepository) FindByUUID(_ context.Context, orderUUID string) (*models.Order, error) {
+	for _, order := range f.orders {
+		if order.UUID.String() == orderUUID {
+			return &order, nil
+		}
+	}
+	return nil, errOrder.ErrOrderNotFound
+}
+
+type fakeClientRegistry struct {
+	user *fakeUserClient
+}
+
+func (f *fakeClientRegistry) GetUser() clientUser.IUserClient {
+	return f.user
+}
+
+func (f *fakeClientRegistry) GetPayment() clientPayment.IPaymentClient {
+	return nil
+}
+
+func (f *fakeClientRegistry) GetField() clientField.IFieldClient {
+	return nil
+}
+
+var _ clients.IClientRegistry = (*fakeClientRegistry)(nil)
+
+type fakeUserClient struct {
+	clientUser.IUserClient
+	users map[uuid.UUID]*clientUser.UserData
+}
+
+func (f *fakeUserClient) GetUserbyUUID(_ context.Context, userID uuid.UUID) (*clientUser.UserData, error) {
+	return f.users[userID], nil
+}
+
+func (f *fakeUserClient) GetUsersByUUIDs(_ context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*clientUser.UserData, error) {
+	result := map[uuid.UUID]*clientUser.UserData{}
+	for _, userID := range userIDs {
+		if user, ok := f.users[userID]; ok {
+			result[userID] = user
+		}
+	}
+	return result, nil
+}
+
+func newFakeUser(role string) *clientUser.UserData {
+	id := uuid.New()
+	return &clientUser.UserData{UUID: id, Name: role + "-" + id.String()[:8], Role: role}
+}
+
+func newFakeOrder(id uint, userID uuid.UUID) models.Order {
+	now := time.Now()
+	return models.Order{
+		ID:        id,
+		UUID:      uuid.New(),
+		UserID:    userID,
+		Status:    constants.Pending,
+		Date:      now,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+}
+
+func withUser(user *clientUser.UserData) context.Context {
+	return context.WithValue(context.Background(), constants.User, user)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"order-service/constants"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+func (o *OrderService) findFieldScheduleIDs(ctx context.Context, tx *gorm.DB, orderID uint) ([]string, error) {
+	orderFieldSchedules, err := o.repository.GetOrderField().FindByOrderIDTx(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldScheduleIDs := make([]string, 0, len(orderFieldSchedules))
+	for _, item := range orderFieldSchedules {
+		fieldScheduleIDs = append(fieldScheduleIDs, item.FieldScheduleID.String())
+	}
+
+	return fieldScheduleIDs, nil
+}
+
+func (o *OrderService) releaseFieldSchedules(fieldScheduleIDs []string, holdID string) error {
+	if len(fieldScheduleIDs) == 0 {
+		return nil
+	}
+
+	return o.client.GetField().ReleaseStatus(&dto.UpdateFieldScheduleStatusRequest{
+		FieldScheduleIDs: fieldScheduleIDs,
+		HoldID:           holdID,
+	})
+}
+
+type fieldSchedulePayload struct {
+	FieldScheduleIDs []string `json:"field_schedule_ids"`
+	HoldID           string   `json:"hold_id"`
+}
+
+// queueFieldScheduleTask mencatat book/release jadwal di transaksi yang sama dengan perubahan status order,
+// sehingga order dan field service tetap konsisten walaupun field service sedang mati.
+func (o *OrderService) queueFieldScheduleTask(ctx context.Context, tx *gorm.DB, order *models.Order, action constants.OrderTaskAction) (*models.OrderTask, error) {
+	fieldScheduleIDs, err := o.findFieldScheduleIDs(ctx, tx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fieldScheduleIDs) == 0 {
+		return nil, nil
+	}
+
+	return o.queueOrderTask(ctx, tx, order.ID, action, fieldSchedulePayload{
+		FieldScheduleIDs: fieldScheduleIDs,
+		HoldID:           order.Code,
+	})
+}
+
+// decodeFieldSchedulePayload juga menerima payload lama yang hanya berisi daftar jadwal tanpa hold id.
+func decodeFieldSchedulePayload(raw string) (fieldSchedulePayload, error) {
+	var payload fieldSchedulePayload
+	if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		err := json.Unmarshal([]byte(raw), &payload.FieldScheduleIDs)
+		return payload, err
+	}
+
+	err := json.Unmarshal([]byte(raw), &payload)
+	return payload, err
+}
+
+func (o *OrderService) executeFieldScheduleTask(task *models.OrderTask) error {
+	payload, err := decodeFieldSchedulePayload(task.Payload)
+	if err != nil {
+		return err
+	}
+
+	if task.Action == constants.BookFieldScheduleAction {
+		return o.client.GetField().UpdateStatus(&dto.UpdateFieldScheduleStatusRequest{
+			FieldScheduleIDs: payload.FieldScheduleIDs,
+			HoldID:           payload.HoldID,
+		})
+	}
+
+	return o.releaseFieldSchedules(payload.FieldScheduleIDs, payload.HoldID)
+}
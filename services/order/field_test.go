@@ -0,0 +1,50 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeFieldSchedulePayload(t *testing.T) {
+	ids := []string{"5f0c7f5e-0a52-4d7a-9a38-1c7f1f0a6b11", "8d2b6c1e-3f44-4b8e-9e2a-6a0f3c9d7e22"}
+
+	tests := []struct {
+		name    string
+		raw     string
+		holdID  string
+		wantErr bool
+	}{
+		{
+			name:   "with hold id",
+			raw:    `{"field_schedule_ids": ["5f0c7f5e-0a52-4d7a-9a38-1c7f1f0a6b11", "8d2b6c1e-3f44-4b8e-9e2a-6a0f3c9d7e22"], "hold_id": "ORD-00001-20250101"}`,
+			holdID: "ORD-00001-20250101",
+		},
+		{
+			name: "legacy list",
+			raw:  ` ["5f0c7f5e-0a52-4d7a-9a38-1c7f1f0a6b11", "8d2b6c1e-3f44-4b8e-9e2a-6a0f3c9d7e22"]`,
+		},
+		{name: "invalid", raw: `"not a payload"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := decodeFieldSchedulePayload(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected decode error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeFieldSchedulePayload: %v", err)
+			}
+
+			if !reflect.DeepEqual(payload.FieldScheduleIDs, ids) {
+				t.Errorf("field schedule ids = %v, want %v", payload.FieldScheduleIDs, ids)
+			}
+			if payload.HoldID != tt.holdID {
+				t.Errorf("hold id = %q, want %q", payload.HoldID, tt.holdID)
+			}
+		})
+	}
+}
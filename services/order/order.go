@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"order-service/clients"
@@ -9,7 +10,9 @@ import (
 	clientPayment "order-service/clients/payment"
 	clientUser "order-service/clients/user"
 	"order-service/common/util"
+	"order-service/config"
 	"order-service/constants"
+	errConstant "order-service/constants/error"
 	errOrder "order-service/constants/error/order"
 	"order-service/domain/dto"
 	"order-service/domain/models"
@@ -18,6 +21,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -28,10 +32,17 @@ type OrderService struct {
 
 type IOrderService interface {
 	GetAllWithPagination(context.Context, *dto.OrderRequestParam) (*util.PaginationResult, error)
-	GetByUUID(context.Context, string) (*dto.OrderResponse, error)
-	GetOrdersByUserID(context.Context) ([]dto.OrderByUserIDResponse, error)
+	GetByUUID(context.Context, string, *dto.OrderDetailRequestParam) (*dto.OrderResponse, error)
+	GetAllWithCursor(context.Context, *dto.OrderRequestParam) (*util.CursorPaginationResult, error)
+	GetOrdersByUserID(context.Context, *dto.OrderHistoryRequestParam) (*util.CursorPaginationResult, error)
 	Create(context.Context, *dto.OrderRequest) (*dto.OrderResponse, error)
+	Cancel(context.Context, string) (*dto.OrderResponse, error)
 	HandlePayment(context.Context, *dto.PaymentData) error
+	ExpireStaleOrders(context.Context) (int, error)
+	RecoverIncompleteOrders(context.Context) (int, error)
+	RetryOrderTasks(context.Context) (int, error)
+	PlanPayment(context.Context, *dto.PaymentData) (*dto.PaymentPlan, error)
+	BackfillPaymentLinks(context.Context, int) (int, error)
 }
 
 func NewOrderService(repo repositories.IRepositoryRegistry, client clients.IClientRegistry) IOrderService {
@@ -42,28 +53,19 @@ func NewOrderService(repo repositories.IRepositoryRegistry, client clients.IClie
 }
 
 func (o *OrderService) GetAllWithPagination(ctx context.Context, param *dto.OrderRequestParam) (*util.PaginationResult, error) {
-	orders, total, err := o.repository.GetOrder().FindAllWithPagination(ctx, param)
+	err := o.scopeOrderParam(ctx, param)
 	if err != nil {
 		return nil, err
 	}
 
-	orderResult := make([]dto.OrderResponse, len(orders))
-	for _, order := range orders {
-		user, err := o.client.GetUser().GetUserbyUUID(ctx, order.UserID)
-		if err != nil {
-			return nil, err
-		}
+	orders, total, err := o.repository.GetOrder().FindAllWithPagination(ctx, param)
+	if err != nil {
+		return nil, err
+	}
 
-		orderResult = append(orderResult, dto.OrderResponse{
-			UUID:      order.UUID,
-			Code:      order.Code,
-			UserName:  user.Name,
-			Amount:    order.Amount,
-			Status:    order.Status.GetStatusString(),
-			OrderDate: order.Date,
-			CreatedAt: *order.CreatedAt,
-			UpdatedAt: *order.UpdatedAt,
-		})
+	orderResult, err := o.toOrderResponses(ctx, orders)
+	if err != nil {
+		return nil, err
 	}
 
 	pagination := util.PaginationParams{
@@ -77,67 +79,222 @@ func (o *OrderService) GetAllWithPagination(ctx context.Context, param *dto.Orde
 	return &response, nil
 }
 
-func (o *OrderService) GetByUUID(ctx context.Context, orderUUID string) (*dto.OrderResponse, error) {
+func (o *OrderService) GetAllWithCursor(ctx context.Context, param *dto.OrderRequestParam) (*util.CursorPaginationResult, error) {
+	err := o.scopeOrderParam(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, pagination, err := o.findWithCursor(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	orderResult, err := o.toOrderResponses(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Data = orderResult
+	response := util.GenerateCursorPagination(pagination)
+	return &response, nil
+}
+
+// scopeOrderParam membatasi customer ke order miliknya, admin boleh memfilter berdasarkan userUUID.
+func (o *OrderService) scopeOrderParam(ctx context.Context, param *dto.OrderRequestParam) error {
+	user, err := o.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	param.UserID = nil
+	if !o.isAdmin(user) {
+		param.UserID = &user.UUID
+		return nil
+	}
+
+	if param.UserUUID != nil {
+		userID, err := uuid.Parse(*param.UserUUID)
+		if err != nil {
+			return errConstant.ErrBadRequest.Wrap(err)
+		}
+		param.UserID = &userID
+	}
+
+	return nil
+}
+
+func (o *OrderService) findWithCursor(ctx context.Context, param *dto.OrderRequestParam) ([]models.Order, util.CursorPaginationParams, error) {
+	var (
+		cursor     *util.Cursor
+		pagination util.CursorPaginationParams
+		err        error
+	)
+
+	if param.Limit <= 0 {
+		param.Limit = constants.DefaultCursorLimit
+	}
+
+	if param.Cursor != nil && *param.Cursor != "" {
+		cursor, err = util.DecodeCursor(*param.Cursor)
+		if err != nil {
+			return nil, pagination, errOrder.ErrInvalidCursor.Wrap(err)
+		}
+	}
+
+	orders, hasMore, err := o.repository.GetOrder().FindAllWithCursor(ctx, param, cursor)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	pagination = util.CursorPaginationParams{
+		Limit:     param.Limit,
+		Prev:      param.Direction == constants.CursorPrev,
+		HasCursor: cursor != nil,
+		HasMore:   hasMore,
+	}
+	if len(orders) > 0 {
+		pagination.First = orderCursor(orders[0])
+		pagination.Last = orderCursor(orders[len(orders)-1])
+	}
+
+	return orders, pagination, nil
+}
+
+func orderCursor(order models.Order) *util.Cursor {
+	cursor := util.Cursor{ID: order.ID}
+	if order.CreatedAt != nil {
+		cursor.CreatedAt = *order.CreatedAt
+	}
+	return &cursor
+}
+
+// toOrderResponses mengambil semua user dalam satu halaman sekaligus lalu menyusun response
+// dengan urutan yang sama seperti orders.
+func (o *OrderService) toOrderResponses(ctx context.Context, orders []models.Order) ([]dto.OrderResponse, error) {
+	ctx = clientUser.WithUserCache(ctx)
+
+	userIDs := make([]uuid.UUID, 0, len(orders))
+	for _, order := range orders {
+		userIDs = append(userIDs, order.UserID)
+	}
+
+	users, err := o.client.GetUser().GetUsersByUUIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	orderResult := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		var userName string
+		if user, ok := users[order.UserID]; ok {
+			userName = user.Name
+		}
+
+		orderResult = append(orderResult, dto.OrderResponse{
+			UUID:        order.UUID,
+			Code:        order.Code,
+			UserName:    userName,
+			Amount:      order.Amount,
+			Status:      order.Status.GetStatusString(),
+			PaymentLink: stringValue(order.PaymentLink),
+			OrderDate:   order.Date,
+			CreatedAt:   *order.CreatedAt,
+			UpdatedAt:   *order.UpdatedAt,
+		})
+	}
+
+	return orderResult, nil
+}
+
+func (o *OrderService) GetByUUID(ctx context.Context, orderUUID string, param *dto.OrderDetailRequestParam) (*dto.OrderResponse, error) {
 	var (
 		order *models.Order
 		user  *clientUser.UserData
 		err   error
 	)
 
+	currentUser, err := o.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	order, err = o.repository.GetOrder().FindByUUID(ctx, orderUUID)
 	if err != nil {
 		return nil, err
 	}
 
+	err = o.authorizeOrder(currentUser, order)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err = o.client.GetUser().GetUserbyUUID(ctx, order.UserID)
 	if err != nil {
 		return nil, err
 	}
 
 	response := dto.OrderResponse{
-		UUID:      order.UUID,
-		Code:      order.Code,
-		UserName:  user.Name,
-		Amount:    order.Amount,
-		Status:    order.Status.GetStatusString(),
-		OrderDate: order.Date,
-		CreatedAt: *order.CreatedAt,
-		UpdatedAt: *order.UpdatedAt,
+		UUID:        order.UUID,
+		Code:        order.Code,
+		UserName:    user.Name,
+		Amount:      order.Amount,
+		Status:      order.Status.GetStatusString(),
+		PaymentLink: stringValue(order.PaymentLink),
+		OrderDate:   order.Date,
+		CreatedAt:   *order.CreatedAt,
+		UpdatedAt:   *order.UpdatedAt,
+	}
+
+	if param != nil && param.Has(constants.OrderExpandFields) {
+		response.Fields, err = o.getOrderFields(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if param != nil && param.Has(constants.OrderExpandHistory) {
+		response.Histories, err = o.getOrderHistories(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &response, nil
 }
 
-func (o *OrderService) GetOrdersByUserID(ctx context.Context) ([]dto.OrderByUserIDResponse, error) {
-	var (
-		order []models.Order
-		user  = ctx.Value(constants.User).(*clientUser.UserData)
-		err   error
-	)
-
-	order, err = o.repository.GetOrder().FindByUserID(ctx, user.UUID.String())
+func (o *OrderService) GetOrdersByUserID(ctx context.Context, param *dto.OrderHistoryRequestParam) (*util.CursorPaginationResult, error) {
+	user, err := o.currentUser(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	orderResult := make([]dto.OrderByUserIDResponse, 0, len(order))
-	for _, ord := range order {
-		payment, err := o.client.GetPayment().GetPaymentByUUID(ctx, ord.PaymentID)
-		if err != nil {
-			return nil, err
-		}
+	orders, pagination, err := o.findWithCursor(ctx, &dto.OrderRequestParam{
+		Limit:      param.Limit,
+		Pagination: constants.PaginationCursor,
+		Cursor:     param.Cursor,
+		Direction:  param.Direction,
+		UserID:     &user.UUID,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	orderResult := make([]dto.OrderByUserIDResponse, 0, len(orders))
+	for _, ord := range orders {
 		orderResult = append(orderResult, dto.OrderByUserIDResponse{
 			Code:        ord.Code,
 			Amount:      fmt.Sprintf("%s", util.RupiahFormat(&ord.Amount)),
 			Status:      ord.Status.GetStatusString(),
 			OrderDate:   ord.Date,
-			PaymentLink: payment.PaymentLink,
-			InvoiceLink: payment.InvoiceLink,
+			PaymentLink: stringValue(ord.PaymentLink),
+			InvoiceLink: ord.InvoiceLink,
 		})
 	}
 
-	return orderResult, nil
+	pagination.Data = orderResult
+	response := util.GenerateCursorPagination(pagination)
+	return &response, nil
 }
 
 func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dto.OrderResponse, error) {
@@ -148,15 +305,21 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 		field               *clientField.FieldData
 		paymentResponse     *clientPayment.PaymentData
 		orderFieldSchedules = make([]models.OrderField, 0, len(param.FieldScheduleIDs))
+		itemDetails         = make([]dto.ItemDetails, 0, len(param.FieldScheduleIDs))
 		totalAmount         float64
 	)
 
 	log.Printf("🟢 Create order request: %+v\n", param)
 	log.Printf("👤 User from context: %+v\n", user)
 
-	for _, fieldID := range param.FieldScheduleIDs {
+	fieldScheduleIDs, err := parseFieldScheduleIDs(param.FieldScheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uuidParsed := range fieldScheduleIDs {
+		fieldID := uuidParsed.String()
 		log.Printf("🔎 Fetching field data for UUID: %s\n", fieldID)
-		uuidParsed := uuid.MustParse(fieldID)
 
 		field, err = o.client.GetField().GetFieldByUUID(ctx, uuidParsed)
 		if err != nil {
@@ -166,12 +329,12 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 
 		if field.PricePerHour <= 0 {
 			log.Printf("❌ Field %s has invalid price: %.2f", field.UUID, field.PricePerHour)
-			return nil, fmt.Errorf("invalid price for field: %s", field.UUID)
+			return nil, errOrder.ErrInvalidFieldPrice
 		}
 
 		if strings.TrimSpace(field.FieldName) == "" {
 			log.Printf("❌ Field name is empty for field %s\n", field.UUID)
-			return nil, fmt.Errorf("field name cannot be empty for field %s", field.UUID)
+			return nil, errOrder.ErrInvalidFieldName
 		}
 
 		log.Printf("✅ Field data: %+v\n", field)
@@ -182,24 +345,57 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 		}
 
 		totalAmount += field.PricePerHour
+		orderField := newOrderFieldSnapshot(field)
+		orderField.FieldScheduleID = uuidParsed
+		orderFieldSchedules = append(orderFieldSchedules, orderField)
+		itemDetails = append(itemDetails, dto.ItemDetails{
+			ID:       uuidParsed,
+			Name:     fmt.Sprintf("%s %s %s-%s", field.FieldName, field.Date, field.StartTime, field.EndTime),
+			Amount:   field.PricePerHour,
+			Quantity: 1,
+		})
 	}
 
 	log.Printf("💰 Total order amount: %.2f\n", totalAmount)
 
 	if strings.TrimSpace(user.PhoneNumber) == "" {
 		log.Printf("❌ Phone number is empty for user: %s\n", user.UUID)
-		return nil, fmt.Errorf("user phone number is required")
+		return nil, errOrder.ErrPhoneNumberMissing
 	}
 
+	// kode dibuat sebelum transaksi supaya nextval tidak mengambil koneksi kedua dari pool
+	code, err := o.repository.GetOrder().GenerateCode(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to generate order code: %v\n", err)
+		return nil, err
+	}
+
+	expiredAt := time.Unix(time.Now().Add(config.Config.Order.PaymentExpiry()).Unix(), 0)
+
+	// Step 0: tahan jadwal di field service selama batas waktu pembayaran
+	log.Printf("⏳ Holding field schedules until %s\n", expiredAt.Format(time.RFC3339))
+	err = o.client.GetField().HoldStatus(&dto.HoldFieldScheduleRequest{
+		FieldScheduleIDs: param.FieldScheduleIDs,
+		HoldID:           code,
+		ExpiredAt:        expiredAt,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to hold field schedules: %v\n", err)
+		return nil, err
+	}
+
+	// Step 1: simpan order lalu commit, belum ada panggilan ke service lain di dalam transaksi
 	err = o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
 		log.Println("🚧 Starting DB transaction")
 
 		order, txErr = o.repository.GetOrder().Create(ctx, tx, &models.Order{
-			UserID: user.UUID,
-			Amount: totalAmount,
-			Date:   time.Now(),
-			Status: constants.Pending,
-			IsPaid: false,
+			Code:      code,
+			UserID:    user.UUID,
+			Amount:    totalAmount,
+			Date:      time.Now(),
+			Status:    constants.Pending,
+			IsPaid:    false,
+			ExpiredAt: &expiredAt,
 		})
 		if txErr != nil {
 			log.Printf("❌ Failed to create order: %v\n", txErr)
@@ -207,12 +403,8 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 		}
 		log.Printf("✅ Created order: %+v\n", order)
 
-		for _, fieldID := range param.FieldScheduleIDs {
-			uuidParsed := uuid.MustParse(fieldID)
-			orderFieldSchedules = append(orderFieldSchedules, models.OrderField{
-				OrderID:         order.ID,
-				FieldScheduleID: uuidParsed,
-			})
+		for i := range orderFieldSchedules {
+			orderFieldSchedules[i].OrderID = order.ID
 		}
 
 		log.Printf("📌 Creating order-field schedule relation: %+v\n", orderFieldSchedules)
@@ -232,68 +424,92 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 			return txErr
 		}
 
-		expiredAt := time.Now().Add(1 * time.Hour)
-		description := fmt.Sprintf("Pembayaran sewa %s", field.FieldName)
-
-		// 🔍 Buat dan log payload payment
-		paymentRequest := &dto.PaymentRequest{
-			OrderID:     order.UUID,
-			ExpiredAt:   time.Unix(expiredAt.Unix(), 0),
-			Amount:      order.Amount,
-			Description: description,
-			CustomerDetail: dto.CustomerDetail{
-				Name:  user.Name,
-				Email: user.Email,
-				Phone: user.PhoneNumber,
-			},
-			ItemDetails: []dto.ItemDetails{
-				{
-					ID:       uuid.New(),
-					Name:     description,
-					Amount:   totalAmount,
-					Quantity: 1,
-				},
-			},
-		}
-
-		log.Printf("📤 Payment Request Payload:\nOrderID: %s\nExpiredAt: %s\nAmount: %.2f\nDescription: %q\nCustomer: %s / %s / %s\nItems: %+v\n",
-			paymentRequest.OrderID,
-			paymentRequest.ExpiredAt.Format(time.RFC3339),
-			paymentRequest.Amount,
-			paymentRequest.Description,
-			paymentRequest.CustomerDetail.Name,
-			paymentRequest.CustomerDetail.Email,
-			paymentRequest.CustomerDetail.Phone,
-			paymentRequest.ItemDetails,
-		)
-
-		// 🔗 Kirim request payment link
-		paymentResponse, txErr = o.client.GetPayment().CreatePaymentLink(ctx, paymentRequest)
+		txErr = o.enqueueEvent(ctx, tx, order)
 		if txErr != nil {
-			log.Printf("❌ Failed to create payment link: %v\n", txErr)
+			log.Printf("❌ Failed to write order.created event: %v\n", txErr)
 			return txErr
 		}
 
-		log.Printf("✅ Payment link created: %+v\n", paymentResponse)
+		return o.recordStep(ctx, tx, order.ID, constants.CreateOrderStep, nil)
+	})
+
+	if err != nil {
+		log.Printf("❌ Error in Create order transaction: %v\n", err)
+		// jadwal yang sudah dipakai order lain tidak boleh dilepas, hold-nya bukan milik order ini
+		if !errors.Is(err, errOrder.ErrFieldAlreadyBooked) {
+			if releaseErr := o.releaseFieldSchedules(param.FieldScheduleIDs, code); releaseErr != nil {
+				log.Printf("❌ Failed to release held field schedules: %v\n", releaseErr)
+			}
+		}
+		return nil, err
+	}
+
+	log.Println("✅ Order committed successfully")
+
+	// Step 2: minta payment link setelah order tersimpan
+	description := buildPaymentDescription(orderFieldSchedules)
 
-		log.Println("🔄 Updating order with payment UUID")
+	// 🔍 Buat dan log payload payment
+	paymentRequest := &dto.PaymentRequest{
+		OrderID:     order.UUID,
+		ExpiredAt:   expiredAt,
+		Amount:      order.Amount,
+		Description: description,
+		CustomerDetail: dto.CustomerDetail{
+			Name:  user.Name,
+			Email: user.Email,
+			Phone: user.PhoneNumber,
+		},
+		ItemDetails: itemDetails,
+	}
+
+	log.Printf("📤 Payment Request Payload:\nOrderID: %s\nExpiredAt: %s\nAmount: %.2f\nDescription: %q\nCustomer: %s / %s / %s\nItems: %+v\n",
+		paymentRequest.OrderID,
+		paymentRequest.ExpiredAt.Format(time.RFC3339),
+		paymentRequest.Amount,
+		paymentRequest.Description,
+		paymentRequest.CustomerDetail.Name,
+		paymentRequest.CustomerDetail.Email,
+		paymentRequest.CustomerDetail.Phone,
+		paymentRequest.ItemDetails,
+	)
+
+	// 🔗 Kirim request payment link
+	paymentResponse, err = o.client.GetPayment().CreatePaymentLink(ctx, paymentRequest)
+	o.recordPaymentLinkStep(ctx, order.ID, paymentResponse, err)
+	if err != nil {
+		log.Printf("❌ Failed to create payment link: %v\n", err)
+		_ = o.compensateMarkOrderFailed(ctx, order, uuid.Nil)
+		return nil, err
+	}
+
+	log.Printf("✅ Payment link created: %+v\n", paymentResponse)
+
+	// Step 3: simpan payment ID ke order
+	log.Println("🔄 Updating order with payment UUID")
+	err = o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
 		txErr = o.repository.GetOrder().Update(ctx, tx, &models.Order{
-			PaymentID: paymentResponse.UUID,
+			PaymentID:   paymentResponse.UUID,
+			PaymentLink: &paymentResponse.PaymentLink,
+			InvoiceLink: paymentResponse.InvoiceLink,
 		}, order.UUID)
 		if txErr != nil {
-			log.Printf("❌ Failed to update order with payment ID: %v\n", txErr)
 			return txErr
 		}
 
-		log.Println("✅ Transaction committed successfully")
-		return nil
+		return o.recordStep(ctx, tx, order.ID, constants.AttachPaymentStep, nil)
 	})
-
 	if err != nil {
-		log.Printf("❌ Error in Create order transaction: %v\n", err)
+		log.Printf("❌ Failed to update order with payment ID: %v\n", err)
+		_ = o.recordStep(ctx, o.repository.GetTx(), order.ID, constants.AttachPaymentStep, err)
+		_ = o.compensateMarkOrderFailed(ctx, order, paymentResponse.UUID)
 		return nil, err
 	}
 
+	order.PaymentID = paymentResponse.UUID
+	order.PaymentLink = &paymentResponse.PaymentLink
+	order.InvoiceLink = paymentResponse.InvoiceLink
+
 	response := &dto.OrderResponse{
 		UUID:        order.UUID,
 		Code:        order.Code,
@@ -310,54 +526,168 @@ func (o *OrderService) Create(ctx context.Context, param *dto.OrderRequest) (*dt
 	return response, nil
 }
 
-func (o *OrderService) mapPaymentStatusToOrder(request *dto.PaymentData) (constants.OrderStatus, *models.Order) {
+func (o *OrderService) Cancel(ctx context.Context, orderUUID string) (*dto.OrderResponse, error) {
 	var (
-		status constants.OrderStatus
-		order  *models.Order
+		order      *models.Order
+		task       *models.OrderTask
+		voidTask   *models.OrderTask
+		err, txErr error
 	)
 
-	// apa yang sedang diikirm dari payment service di kafka
-	switch request.Status {
-	case constants.SettlementPaymentStatus:
-		status = constants.PaymentSuccess
-		order = &models.Order{
-			IsPaid:    true,
-			PaymentID: request.PaymentID,
-			PaidAt:    request.PaidAt,
-			Status:    status,
-		}
-	case constants.ExpiredPaymentStatus:
-		status = constants.Expired
-		order = &models.Order{
-			IsPaid:    false,
-			PaymentID: request.PaymentID,
-			Status:    status,
+	user, err := o.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userName := user.Name
+
+	order, err = o.repository.GetOrder().FindByUUID(ctx, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.authorizeOrder(user, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		order, txErr = o.repository.GetOrder().FindByUUIDForUpdate(ctx, tx, orderUUID)
+		if txErr != nil {
+			return txErr
 		}
-	case constants.PendingPaymentStatus:
-		status = constants.PendingPayment
-		order = &models.Order{
-			IsPaid:    false,
-			PaymentID: request.PaymentID,
-			Status:    status,
+
+		txErr = o.validateTransition(order, constants.Cancelled)
+		if txErr != nil {
+			return txErr
 		}
+
+		body := &models.Order{
+			Status: constants.Cancelled,
+		}
+		txErr = o.repository.GetOrder().Update(ctx, tx, body, order.UUID)
+		if txErr != nil {
+			return txErr
+		}
+
+		o.applyOrderUpdate(order, body)
+		txErr = o.enqueueEvent(ctx, tx, order)
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderHistory().Create(ctx, tx, &dto.OrderHistoryRequest{
+			Status:  constants.Cancelled.GetStatusString(),
+			OrderID: order.ID,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderField().Deactivate(ctx, tx, order.ID)
+		if txErr != nil {
+			return txErr
+		}
+
+		voidTask, txErr = o.queueVoidPaymentTask(ctx, tx, order.ID, order.PaymentID)
+		if txErr != nil {
+			return txErr
+		}
+
+		task, txErr = o.queueFieldScheduleTask(ctx, tx, order, constants.ReleaseFieldScheduleAction)
+		return txErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// payment dan jadwal dilepas setelah commit, yang gagal dicoba ulang oleh worker
+	o.dispatchOrderTask(ctx, voidTask)
+	o.dispatchOrderTask(ctx, task)
+
+	if order.UserID != user.UUID {
+		owner, err := o.client.GetUser().GetUserbyUUID(ctx, order.UserID)
+		if err != nil {
+			return nil, err
+		}
+		userName = owner.Name
 	}
-	return status, order
+
+	now := time.Now()
+	response := &dto.OrderResponse{
+		UUID:      order.UUID,
+		Code:      order.Code,
+		UserName:  userName,
+		Amount:    order.Amount,
+		Status:    constants.Cancelled.GetStatusString(),
+		OrderDate: order.Date,
+		CreatedAt: *order.CreatedAt,
+		UpdatedAt: now,
+	}
+
+	return response, nil
 }
 
 func (o *OrderService) HandlePayment(ctx context.Context, request *dto.PaymentData) error {
 	var (
-		err, txErr          error
-		order               *models.Order
-		orderFieldSchedules []models.OrderField
+		err, txErr error
+		order      *models.Order
+		task       *models.OrderTask
+		isNewEvent bool
+		eventKey   = o.paymentEventKey(request)
 	)
-	status, body := o.mapPaymentStatusToOrder(request)
+
+	status, body, err := o.paymentStatusToOrder(request)
+	if err != nil {
+		return err
+	}
+
 	err = o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		order, txErr = o.repository.GetOrder().FindByUUIDForUpdate(ctx, tx, request.OrderID.String())
+		if txErr != nil {
+			return txErr
+		}
+
+		isNewEvent, txErr = o.repository.GetProcessedEvent().Create(ctx, tx, &models.ProcessedEvent{
+			EventKey: eventKey,
+			OrderID:  order.ID,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		if !isNewEvent {
+			logrus.Infof("[OrderService-HandlePayment] payment event %s already processed, skipping", eventKey)
+			return nil
+		}
+
+		if remark, stale := o.stalePaymentEvent(order, request); stale {
+			// event tetap dicatat di history untuk audit, status order tidak berubah
+			logrus.Warnf("[OrderService-HandlePayment] order %s: %s", order.UUID, remark)
+			return o.repository.GetOrderHistory().Create(ctx, tx, &dto.OrderHistoryRequest{
+				Status:  order.Status.GetStatusString(),
+				OrderID: order.ID,
+				Remark:  &remark,
+			})
+		}
+
+		if status == constants.PaymentSuccess && order.Status.IsFinal() && order.Status != constants.PaymentSuccess {
+			logrus.Errorf("[OrderService-HandlePayment] payment %s settled for %s order %s",
+				request.PaymentID, order.Status.GetStatusString(), order.UUID)
+			return errOrder.ErrPaymentOnClosedOrder
+		}
+
+		txErr = o.validateTransition(order, status)
+		if txErr != nil {
+			return txErr
+		}
+
 		txErr = o.repository.GetOrder().Update(ctx, tx, body, request.OrderID)
 		if txErr != nil {
 			return txErr
 		}
 
-		order, txErr = o.repository.GetOrder().FindByUUID(ctx, request.OrderID.String())
+		o.applyOrderUpdate(order, body)
+		txErr = o.enqueueEvent(ctx, tx, order)
 		if txErr != nil {
 			return txErr
 		}
@@ -366,29 +696,33 @@ func (o *OrderService) HandlePayment(ctx context.Context, request *dto.PaymentDa
 			Status:  status.GetStatusString(),
 			OrderID: order.ID,
 		})
+		if txErr != nil {
+			return txErr
+		}
 
-		if request.Status == constants.SettlementPaymentStatus {
-			orderFieldSchedules, txErr = o.repository.GetOrderField().FindByOrderID(ctx, order.ID)
-			if txErr != nil {
-				return txErr
-			}
-
-			filedScheduleIDs := make([]string, 0, len(orderFieldSchedules))
-			for _, item := range orderFieldSchedules {
-				filedScheduleIDs = append(filedScheduleIDs, item.FieldScheduleID.String())
-			}
-
-			txErr = o.client.GetField().UpdateStatus(&dto.UpdateFieldScheduleStatusRequest{
-				FieldScheduleIDs: filedScheduleIDs,
-			})
+		switch status {
+		case constants.PaymentSuccess:
+			task, txErr = o.queueFieldScheduleTask(ctx, tx, order, constants.BookFieldScheduleAction)
+		case constants.Expired:
+			txErr = o.repository.GetOrderField().Deactivate(ctx, tx, order.ID)
 			if txErr != nil {
 				return txErr
 			}
+			task, txErr = o.queueFieldScheduleTask(ctx, tx, order, constants.ReleaseFieldScheduleAction)
 		}
-		return nil
+		return txErr
 	})
 	if err != nil {
 		return err
 	}
+
+	o.dispatchOrderTask(ctx, task)
 	return nil
 }
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// enqueueEvent menulis event ke order_outbox di transaksi yang sama dengan perubahan order,
+// event baru dipublish oleh relay setelah transaksi commit.
+func (o *OrderService) enqueueEvent(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+	eventName, ok := order.Status.GetEventName()
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(dto.OrderEventData{
+		UUID:      order.UUID,
+		Code:      order.Code,
+		UserID:    order.UserID,
+		PaymentID: order.PaymentID,
+		Amount:    order.Amount,
+		Status:    order.Status.GetStatusString(),
+		IsPaid:    order.IsPaid,
+		OrderDate: order.Date,
+		PaidAt:    order.PaidAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.repository.GetOrderOutbox().Create(ctx, tx, &models.OrderOutbox{
+		OrderID:   order.ID,
+		OrderUUID: order.UUID,
+		EventName: eventName.String(),
+		Payload:   string(payload),
+	})
+}
+
+// applyOrderUpdate menyamakan order yang sudah di-load dengan kolom yang di-update,
+// mengikuti perilaku Updates gorm yang mengabaikan zero value.
+func (o *OrderService) applyOrderUpdate(order, body *models.Order) {
+	if body.Status != 0 {
+		order.Status = body.Status
+	}
+	if body.PaymentID != uuid.Nil {
+		order.PaymentID = body.PaymentID
+	}
+	if body.IsPaid {
+		order.IsPaid = true
+	}
+	if body.PaidAt != nil {
+		order.PaidAt = body.PaidAt
+	}
+	if body.PaymentLink != nil {
+		order.PaymentLink = body.PaymentLink
+	}
+	if body.InvoiceLink != nil {
+		order.InvoiceLink = body.InvoiceLink
+	}
+	if body.LastPaymentEventAt != nil {
+		order.LastPaymentEventAt = body.LastPaymentEventAt
+	}
+	if body.LastPaymentEventSeq != nil {
+		order.LastPaymentEventSeq = body.LastPaymentEventSeq
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/constants"
+	errOrder "order-service/constants/error/order"
+	"order-service/domain/models"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxPaymentDescriptionLength menjaga deskripsi tetap muat di payment gateway.
+const maxPaymentDescriptionLength = 255
+
+// parseFieldScheduleIDs memastikan setiap jadwal valid dan hanya muncul sekali dalam satu order.
+func parseFieldScheduleIDs(fieldScheduleIDs []string) ([]uuid.UUID, error) {
+	if len(fieldScheduleIDs) == 0 {
+		return nil, errOrder.ErrInvalidFieldSchedule
+	}
+
+	var (
+		result = make([]uuid.UUID, 0, len(fieldScheduleIDs))
+		seen   = make(map[uuid.UUID]bool, len(fieldScheduleIDs))
+	)
+	for _, fieldScheduleID := range fieldScheduleIDs {
+		id, err := uuid.Parse(fieldScheduleID)
+		if err != nil {
+			return nil, errOrder.ErrInvalidFieldSchedule.Wrap(err)
+		}
+
+		if seen[id] {
+			return nil, errOrder.ErrInvalidFieldSchedule.Wrap(fmt.Errorf("duplicate field schedule %s", id))
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	return result, nil
+}
+
+// buildPaymentDescription merangkum semua lapangan di order, misalnya
+// "Pembayaran sewa Lapangan A (2 jadwal), Lapangan B (1 jadwal)".
+func buildPaymentDescription(orderFields []models.OrderField) string {
+	var (
+		names  = make([]string, 0, len(orderFields))
+		counts = make(map[string]int, len(orderFields))
+	)
+	for _, orderField := range orderFields {
+		if counts[orderField.FieldName] == 0 {
+			names = append(names, orderField.FieldName)
+		}
+		counts[orderField.FieldName]++
+	}
+
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, fmt.Sprintf("%s (%d jadwal)", name, counts[name]))
+	}
+
+	description := fmt.Sprintf("Pembayaran sewa %s", strings.Join(fields, ", "))
+	if runes := []rune(description); len(runes) > maxPaymentDescriptionLength {
+		description = string(runes[:maxPaymentDescriptionLength-3]) + "..."
+	}
+
+	return description
+}
+
+type voidPaymentPayload struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+}
+
+// queueVoidPaymentTask mencatat pembatalan payment di transaksi yang mengubah status order, payment service
+// baru dipanggil setelah commit supaya payment tidak di-void untuk order yang statusnya gagal berubah.
+func (o *OrderService) queueVoidPaymentTask(ctx context.Context, tx *gorm.DB, orderID uint, paymentID uuid.UUID) (*models.OrderTask, error) {
+	if paymentID == uuid.Nil {
+		return nil, nil
+	}
+
+	return o.queueOrderTask(ctx, tx, orderID, constants.VoidPaymentAction, voidPaymentPayload{PaymentID: paymentID})
+}
+
+func (o *OrderService) executeVoidPaymentTask(ctx context.Context, task *models.OrderTask) error {
+	var payload voidPaymentPayload
+	err := json.Unmarshal([]byte(task.Payload), &payload)
+	if err != nil {
+		return err
+	}
+
+	if payload.PaymentID == uuid.Nil {
+		return fmt.Errorf("void payment task %d has no payment id", task.ID)
+	}
+
+	return o.client.GetPayment().CancelPayment(ctx, payload.PaymentID)
+}
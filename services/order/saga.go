@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	clientPayment "order-service/clients/payment"
+	"order-service/config"
+	"order-service/constants"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// recordStep mencatat langkah saga pembuatan order supaya kegagalan di tengah jalan bisa ditelusuri
+// dan dipulihkan. Kegagalan mencatat step tidak boleh menggagalkan saga itu sendiri.
+func (o *OrderService) recordStep(ctx context.Context, tx *gorm.DB, orderID uint, step constants.SagaStep, cause error) error {
+	sagaStep := &models.OrderSagaStep{
+		OrderID: orderID,
+		Step:    step,
+		Status:  constants.SagaStepCompleted,
+	}
+
+	if cause != nil {
+		message := cause.Error()
+		sagaStep.Status = constants.SagaStepFailed
+		sagaStep.Error = &message
+	}
+
+	err := o.repository.GetOrderSagaStep().Create(ctx, tx, sagaStep)
+	if err != nil {
+		logrus.Errorf("[OrderService-recordStep] failed to record step %s for order %d: %v", step, orderID, err)
+	}
+
+	return err
+}
+
+// recordPaymentLinkStep menyimpan payment ID di step create_payment_link, supaya payment yang sudah dibuat
+// tetap bisa di-void oleh RecoverIncompleteOrders kalau proses mati sebelum attach_payment.
+func (o *OrderService) recordPaymentLinkStep(ctx context.Context, orderID uint, payment *clientPayment.PaymentData, cause error) {
+	sagaStep := &models.OrderSagaStep{
+		OrderID: orderID,
+		Step:    constants.CreatePaymentLinkStep,
+		Status:  constants.SagaStepCompleted,
+	}
+
+	if cause != nil {
+		message := cause.Error()
+		sagaStep.Status = constants.SagaStepFailed
+		sagaStep.Error = &message
+	} else {
+		sagaStep.PaymentID = &payment.UUID
+	}
+
+	err := o.repository.GetOrderSagaStep().Create(ctx, o.repository.GetTx(), sagaStep)
+	if err != nil {
+		logrus.Errorf("[OrderService-recordPaymentLinkStep] failed to record step for order %d: %v", orderID, err)
+	}
+}
+
+// createdPaymentID mencari payment yang sudah dibuat untuk order tetapi belum terpasang.
+func (o *OrderService) createdPaymentID(ctx context.Context, order *models.Order) (uuid.UUID, error) {
+	if order.PaymentID != uuid.Nil {
+		return order.PaymentID, nil
+	}
+
+	steps, err := o.repository.GetOrderSagaStep().FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	for _, step := range steps {
+		if step.Step == constants.CreatePaymentLinkStep && step.Status == constants.SagaStepCompleted && step.PaymentID != nil {
+			return *step.PaymentID, nil
+		}
+	}
+
+	return uuid.Nil, nil
+}
+
+// RecoverIncompleteOrders menggagalkan order yang saga Create-nya berhenti sebelum payment terpasang,
+// supaya jadwal yang ditahan dilepas tanpa menunggu batas pembayaran habis.
+func (o *OrderService) RecoverIncompleteOrders(ctx context.Context) (int, error) {
+	batchSize := config.Config.Order.ExpirySweepBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpirySweepBatchSize
+	}
+
+	orders, err := o.repository.GetOrder().FindIncompleteSaga(ctx, time.Now().Add(-config.Config.Order.SagaRecoveryGrace()), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for i := range orders {
+		paymentID, err := o.createdPaymentID(ctx, &orders[i])
+		if err != nil {
+			logrus.Errorf("[OrderService-RecoverIncompleteOrders] failed to read saga steps of order %s: %v", orders[i].UUID, err)
+			continue
+		}
+
+		logrus.Warnf("[OrderService-RecoverIncompleteOrders] order %s stopped before attach_payment, marking it failed", orders[i].UUID)
+		if o.compensateMarkOrderFailed(ctx, &orders[i], paymentID) == nil {
+			recovered++
+		}
+	}
+
+	return recovered, nil
+}
+
+// compensateMarkOrderFailed menggagalkan order dan mengantrikan pelepasan jadwal serta void payment
+// (jika paymentID diisi) di transaksi yang sama, supaya keduanya tetap dicoba ulang oleh worker.
+func (o *OrderService) compensateMarkOrderFailed(ctx context.Context, order *models.Order, paymentID uuid.UUID) error {
+	var task, voidTask *models.OrderTask
+
+	err := o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		current, txErr := o.repository.GetOrder().FindByUUIDForUpdate(ctx, tx, order.UUID.String())
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.validateTransition(current, constants.Failed)
+		if txErr != nil {
+			return txErr
+		}
+
+		body := &models.Order{Status: constants.Failed}
+		txErr = o.repository.GetOrder().Update(ctx, tx, body, current.UUID)
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderHistory().Create(ctx, tx, &dto.OrderHistoryRequest{
+			Status:  constants.Failed.GetStatusString(),
+			OrderID: current.ID,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = o.repository.GetOrderField().Deactivate(ctx, tx, current.ID)
+		if txErr != nil {
+			return txErr
+		}
+
+		task, txErr = o.queueFieldScheduleTask(ctx, tx, current, constants.ReleaseFieldScheduleAction)
+		if txErr != nil {
+			return txErr
+		}
+
+		voidTask, txErr = o.queueVoidPaymentTask(ctx, tx, current.ID, paymentID)
+		if txErr != nil {
+			return txErr
+		}
+		if voidTask != nil {
+			txErr = o.recordStep(ctx, tx, current.ID, constants.VoidPaymentStep, nil)
+			if txErr != nil {
+				return txErr
+			}
+		}
+
+		o.applyOrderUpdate(current, body)
+		return o.enqueueEvent(ctx, tx, current)
+	})
+	if err != nil {
+		logrus.Errorf("[OrderService-compensateMarkOrderFailed] failed to mark order %s as failed: %v", order.UUID, err)
+	}
+
+	_ = o.recordStep(ctx, o.repository.GetTx(), order.ID, constants.MarkOrderFailedStep, err)
+	if err != nil {
+		return err
+	}
+
+	// kalau release atau void gagal, task tetap di antrian dan dicoba ulang oleh worker
+	o.dispatchOrderTask(ctx, task)
+	o.dispatchOrderTask(ctx, voidTask)
+	return nil
+}
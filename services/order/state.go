@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/constants"
+	errOrder "order-service/constants/error/order"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (o *OrderService) validateTransition(order *models.Order, next constants.OrderStatus) error {
+	if order.Status.CanTransitionTo(next) {
+		return nil
+	}
+
+	err := &errOrder.StatusTransitionError{
+		From: order.Status.GetStatusString(),
+		To:   next.GetStatusString(),
+	}
+	logrus.Warnf("[OrderService-validateTransition] order %s rejected: %v", order.UUID, err)
+	return err
+}
+
+func (o *OrderService) paymentStatusToOrder(request *dto.PaymentData) (constants.OrderStatus, *models.Order, error) {
+	status, ok := request.Status.GetOrderStatus()
+	if !ok {
+		logrus.Warnf("[OrderService-paymentStatusToOrder] unknown payment status %q for order %s", request.Status, request.OrderID)
+		return 0, nil, errOrder.ErrUnknownPaymentStatus
+	}
+
+	order := &models.Order{
+		PaymentID:   request.PaymentID,
+		Status:      status,
+		PaymentLink: request.PaymentLink,
+		InvoiceLink: request.InvoiceLink,
+	}
+
+	if status == constants.PaymentSuccess {
+		order.IsPaid = true
+		order.PaidAt = request.PaidAt
+	}
+
+	if request.SentAt != nil {
+		sentAt := request.SentAt.UTC()
+		order.LastPaymentEventAt = &sentAt
+	}
+	order.LastPaymentEventSeq = request.Sequence
+
+	return status, order, nil
+}
+
+// stalePaymentEvent mengembalikan alasan jika event lebih lama dari payment event terakhir
+// yang sudah diterapkan ke order. Sequence dipakai jika kedua sisi punya, selain itu waktu kirim.
+func (o *OrderService) stalePaymentEvent(order *models.Order, request *dto.PaymentData) (string, bool) {
+	if request.Sequence != nil && order.LastPaymentEventSeq != nil {
+		if *request.Sequence <= *order.LastPaymentEventSeq {
+			return fmt.Sprintf("ignored stale payment event %s: sequence %d, last applied %d",
+				request.Status, *request.Sequence, *order.LastPaymentEventSeq), true
+		}
+		return "", false
+	}
+
+	if request.SentAt != nil && order.LastPaymentEventAt != nil && request.SentAt.Before(*order.LastPaymentEventAt) {
+		return fmt.Sprintf("ignored stale payment event %s: sent at %s, last applied %s",
+			request.Status, request.SentAt.Format(time.RFC3339), order.LastPaymentEventAt.Format(time.RFC3339)), true
+	}
+
+	return "", false
+}
+
+func (o *OrderService) paymentEventKey(request *dto.PaymentData) string {
+	return fmt.Sprintf("payment:%s:%s", request.PaymentID, request.Status)
+}
+
+// PlanPayment melaporkan apakah payment event akan mengubah status order, tanpa menulis apa pun.
+func (o *OrderService) PlanPayment(ctx context.Context, request *dto.PaymentData) (*dto.PaymentPlan, error) {
+	plan := &dto.PaymentPlan{
+		OrderID:       request.OrderID,
+		PaymentID:     request.PaymentID,
+		PaymentStatus: request.Status,
+	}
+
+	status, _, err := o.paymentStatusToOrder(request)
+	if err != nil {
+		plan.Reason = err.Error()
+		return plan, nil
+	}
+	plan.TargetStatus = status.GetStatusString()
+
+	order, err := o.repository.GetOrder().FindByUUID(ctx, request.OrderID.String())
+	if errors.Is(err, errOrder.ErrOrderNotFound) {
+		plan.Reason = err.Error()
+		return plan, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan.CurrentStatus = order.Status.GetStatusString()
+
+	processed, err := o.repository.GetProcessedEvent().Exists(ctx, o.paymentEventKey(request))
+	if err != nil {
+		return nil, err
+	}
+
+	staleReason, stale := o.stalePaymentEvent(order, request)
+	switch {
+	case processed:
+		plan.Reason = "payment event already processed"
+	case stale:
+		plan.Reason = staleReason
+	case !order.Status.CanTransitionTo(status):
+		plan.Reason = (&errOrder.StatusTransitionError{From: plan.CurrentStatus, To: plan.TargetStatus}).Error()
+	default:
+		plan.WillChange = true
+	}
+
+	return plan, nil
+}
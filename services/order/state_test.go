@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"order-service/schemas"
+	"testing"
+	"time"
+)
+
+func paymentMessage(status, sendingAt string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"event": {"name": "payment-service-callback"},
+		"metadata": {"sender": "payment-service", "sending_at": %q},
+		"body": {"type": "JSON", "data": {
+			"order_id": "5f0c7f5e-0a52-4d7a-9a38-1c7f1f0a6b11",
+			"payment_id": "8d2b6c1e-3f44-4b8e-9e2a-6a0f3c9d7e22",
+			"status": %q
+		}}
+	}`, sendingAt, status))
+}
+
+// storeTimestamp meniru kolom timestamp tanpa zona waktu: offset dibuang dan nilai dibaca kembali sebagai UTC.
+func storeTimestamp(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	stored := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return &stored
+}
+
+func TestStalePaymentEventAfterTimestampRoundTrip(t *testing.T) {
+	service := &OrderService{}
+
+	pending, err := schemas.DecodePayment(paymentMessage("pending", "2025-01-01T10:00:00+07:00"))
+	if err != nil {
+		t.Fatalf("decode pending: %v", err)
+	}
+
+	_, body, err := service.paymentStatusToOrder(&pending.Body.Data)
+	if err != nil {
+		t.Fatalf("paymentStatusToOrder: %v", err)
+	}
+	order := &models.Order{LastPaymentEventAt: storeTimestamp(body.LastPaymentEventAt)}
+
+	tests := []struct {
+		name      string
+		status    string
+		sendingAt string
+		stale     bool
+	}{
+		{name: "settlement sent 3 minutes later", status: "settlement", sendingAt: "2025-01-01T10:03:00+07:00", stale: false},
+		{name: "settlement in another offset", status: "settlement", sendingAt: "2025-01-01T03:03:00Z", stale: false},
+		{name: "pending sent earlier", status: "pending", sendingAt: "2025-01-01T09:59:00+07:00", stale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := schemas.DecodePayment(paymentMessage(tt.status, tt.sendingAt))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			reason, stale := service.stalePaymentEvent(order, &message.Body.Data)
+			if stale != tt.stale {
+				t.Fatalf("stale = %v, want %v (%s)", stale, tt.stale, reason)
+			}
+		})
+	}
+}
+
+func TestStalePaymentEventBySequence(t *testing.T) {
+	service := &OrderService{}
+	last := int64(5)
+	order := &models.Order{LastPaymentEventSeq: &last}
+
+	for _, tt := range []struct {
+		sequence int64
+		stale    bool
+	}{
+		{sequence: 4, stale: true},
+		{sequence: 5, stale: true},
+		{sequence: 6, stale: false},
+	} {
+		sequence := tt.sequence
+		if _, stale := service.stalePaymentEvent(order, &dto.PaymentData{Sequence: &sequence}); stale != tt.stale {
+			t.Errorf("sequence %d: stale = %v, want %v", tt.sequence, stale, tt.stale)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/config"
+	"order-service/constants"
+	"order-service/domain/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	orderTaskBaseBackoff = 30 * time.Second
+	orderTaskMaxBackoff  = 30 * time.Minute
+	// orderTaskLease batas waktu satu batch sebelum task boleh diambil worker lain,
+	// book/release/void aman dijalankan dua kali jika batch berjalan lebih lama
+	orderTaskLease            = 5 * time.Minute
+	defaultOrderTaskBatchSize = 50
+)
+
+// queueOrderTask mencatat efek samping ke service lain di transaksi yang sama dengan perubahan order.
+// Dispatch langsung dicoba setelah commit, worker baru mengambil alih kalau gagal.
+func (o *OrderService) queueOrderTask(ctx context.Context, tx *gorm.DB, orderID uint, action constants.OrderTaskAction, payload any) (*models.OrderTask, error) {
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.repository.GetOrderTask().Create(ctx, tx, &models.OrderTask{
+		OrderID:       orderID,
+		Action:        action,
+		Payload:       string(value),
+		NextAttemptAt: time.Now().Add(orderTaskBaseBackoff),
+	})
+}
+
+func (o *OrderService) dispatchOrderTask(ctx context.Context, task *models.OrderTask) {
+	if task == nil {
+		return
+	}
+
+	err := o.executeOrderTask(ctx, task)
+	if err != nil {
+		logrus.Warnf("[OrderService-dispatchOrderTask] %s task for order %d failed, queued for retry: %v",
+			task.Action, task.OrderID, err)
+		err = o.repository.GetOrderTask().MarkFailed(ctx, o.repository.GetTx(), task.ID, err, o.nextAttemptAt(task.Attempts+1))
+	} else {
+		err = o.repository.GetOrderTask().MarkCompleted(ctx, o.repository.GetTx(), task.ID)
+	}
+
+	if err != nil {
+		logrus.Errorf("[OrderService-dispatchOrderTask] failed to update task %d: %v", task.ID, err)
+	}
+}
+
+func (o *OrderService) executeOrderTask(ctx context.Context, task *models.OrderTask) error {
+	switch task.Action {
+	case constants.BookFieldScheduleAction, constants.ReleaseFieldScheduleAction:
+		return o.executeFieldScheduleTask(task)
+	case constants.VoidPaymentAction:
+		return o.executeVoidPaymentTask(ctx, task)
+	}
+
+	return fmt.Errorf("unknown order task action: %s", task.Action)
+}
+
+func (o *OrderService) nextAttemptAt(attempts int) time.Time {
+	backoff := orderTaskBaseBackoff
+	for i := 1; i < attempts && backoff < orderTaskMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > orderTaskMaxBackoff {
+		backoff = orderTaskMaxBackoff
+	}
+
+	return time.Now().Add(backoff)
+}
+
+// RetryOrderTasks menjalankan ulang task order yang sebelumnya gagal.
+// Batch di-claim dan di-commit lebih dulu, panggilan ke service lain dilakukan di luar transaksi
+// supaya lock dan koneksi DB tidak tertahan selama menunggu timeout.
+func (o *OrderService) RetryOrderTasks(ctx context.Context) (int, error) {
+	var (
+		tasks     []models.OrderTask
+		completed int
+		batchSize = config.Config.Order.FieldSyncBatchSize
+	)
+
+	if batchSize <= 0 {
+		batchSize = defaultOrderTaskBatchSize
+	}
+
+	err := o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var err error
+		tasks, err = o.repository.GetOrderTask().FindDueForUpdate(ctx, tx, time.Now(), batchSize)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+
+		return o.repository.GetOrderTask().Claim(ctx, tx, ids, time.Now().Add(orderTaskLease))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	db := o.repository.GetTx()
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			// task yang belum dikerjakan diambil lagi setelah lease habis
+			break
+		}
+
+		err = o.executeOrderTask(ctx, &task)
+		if err != nil {
+			logrus.Warnf("[OrderService-RetryOrderTasks] %s task for order %d failed on attempt %d: %v",
+				task.Action, task.OrderID, task.Attempts+1, err)
+			err = o.repository.GetOrderTask().MarkFailed(ctx, db, task.ID, err, o.nextAttemptAt(task.Attempts+1))
+		} else {
+			err = o.repository.GetOrderTask().MarkCompleted(ctx, db, task.ID)
+			if err == nil {
+				completed++
+			}
+		}
+
+		if err != nil {
+			logrus.Errorf("[OrderService-RetryOrderTasks] failed to update task %d: %v", task.ID, err)
+		}
+	}
+
+	return completed, nil
+}
@@ -0,0 +1,75 @@
+package expiry
+
+import (
+	"context"
+	"order-service/config"
+	"order-service/services"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultIntervalSec = 60
+
+type Sweeper struct {
+	service  services.IServiceRegistry
+	interval time.Duration
+}
+
+type ISweeper interface {
+	Run(context.Context)
+	SweepOnce(context.Context) (int, error)
+}
+
+func NewSweeper(service services.IServiceRegistry) ISweeper {
+	interval := time.Duration(config.Config.Order.ExpirySweepIntervalInSec) * time.Second
+	if interval <= 0 {
+		interval = defaultIntervalSec * time.Second
+	}
+
+	return &Sweeper{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	logrus.Infof("[ExpirySweeper-Run] sweeping stale orders every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("[ExpirySweeper-Run] stopped")
+			return
+		case <-ticker.C:
+			_, err := s.SweepOnce(ctx)
+			if err != nil {
+				logrus.Errorf("[ExpirySweeper-Run] error sweeping stale orders: %v", err)
+			}
+		}
+	}
+}
+
+func (s *Sweeper) SweepOnce(ctx context.Context) (int, error) {
+	// order yang saga Create-nya terhenti tidak perlu menunggu batas pembayaran
+	recovered, err := s.service.GetOrder().RecoverIncompleteOrders(ctx)
+	if err != nil {
+		logrus.Errorf("[ExpirySweeper-SweepOnce] error recovering incomplete orders: %v", err)
+	} else if recovered > 0 {
+		logrus.Infof("[ExpirySweeper-SweepOnce] marked %d incomplete orders as failed", recovered)
+	}
+
+	expired, err := s.service.GetOrder().ExpireStaleOrders(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if expired > 0 {
+		logrus.Infof("[ExpirySweeper-SweepOnce] expired %d stale orders", expired)
+	}
+
+	return expired, nil
+}
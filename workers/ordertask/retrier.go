@@ -0,0 +1,58 @@
+package ordertask
+
+import (
+	"context"
+	"order-service/config"
+	"order-service/services"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultIntervalSec = 30
+
+type Retrier struct {
+	service  services.IServiceRegistry
+	interval time.Duration
+}
+
+type IRetrier interface {
+	Run(context.Context)
+}
+
+func NewRetrier(service services.IServiceRegistry) IRetrier {
+	interval := time.Duration(config.Config.Order.FieldSyncIntervalInSec) * time.Second
+	if interval <= 0 {
+		interval = defaultIntervalSec * time.Second
+	}
+
+	return &Retrier{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (r *Retrier) Run(ctx context.Context) {
+	logrus.Infof("[OrderTaskRetrier-Run] retrying failed order tasks every %s", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("[OrderTaskRetrier-Run] stopped")
+			return
+		case <-ticker.C:
+			completed, err := r.service.GetOrder().RetryOrderTasks(ctx)
+			if err != nil {
+				logrus.Errorf("[OrderTaskRetrier-Run] error retrying order tasks: %v", err)
+				continue
+			}
+
+			if completed > 0 {
+				logrus.Infof("[OrderTaskRetrier-Run] completed %d order tasks", completed)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	clientKafka "order-service/clients/kafka"
+	"order-service/config"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"order-service/repositories"
+	"order-service/schemas"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTopic      = "order-service-event"
+	defaultBatchSize  = 100
+	defaultIntervalMs = 1000
+	// event yang gagal dipublikasikan sebanyak ini diparkir supaya tidak menahan event lain
+	defaultMaxAttempts = 20
+)
+
+type Relay struct {
+	repository  repositories.IRepositoryRegistry
+	producer    clientKafka.IProducer
+	topic       string
+	batchSize   int
+	interval    time.Duration
+	maxAttempts int
+}
+
+type IRelay interface {
+	Run(context.Context)
+	RelayOnce(context.Context) (int, error)
+}
+
+func NewRelay(repository repositories.IRepositoryRegistry, producer clientKafka.IProducer) IRelay {
+	relay := &Relay{
+		repository:  repository,
+		producer:    producer,
+		topic:       config.Config.Kafka.OrderEventTopic,
+		batchSize:   config.Config.Kafka.OutboxBatchSize,
+		interval:    time.Duration(config.Config.Kafka.OutboxIntervalInMs) * time.Millisecond,
+		maxAttempts: config.Config.Kafka.OutboxMaxAttempts,
+	}
+
+	if relay.topic == "" {
+		relay.topic = defaultTopic
+	}
+	if relay.batchSize <= 0 {
+		relay.batchSize = defaultBatchSize
+	}
+	if relay.interval <= 0 {
+		relay.interval = defaultIntervalMs * time.Millisecond
+	}
+	if relay.maxAttempts <= 0 {
+		relay.maxAttempts = defaultMaxAttempts
+	}
+
+	return relay
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	logrus.Infof("[OutboxRelay-Run] publishing order events to topic %s every %s", r.topic, r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("[OutboxRelay-Run] stopped")
+			return
+		case <-ticker.C:
+			_, err := r.RelayOnce(ctx)
+			if err != nil {
+				logrus.Errorf("[OutboxRelay-Run] error relaying order events: %v", err)
+			}
+		}
+	}
+}
+
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		locked, err := r.repository.GetOrderOutbox().TryLockRelay(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			// relay lain sedang mempublikasikan, batch berikutnya dicoba di tick selanjutnya
+			return nil
+		}
+
+		outboxes, err := r.repository.GetOrderOutbox().FindUnpublishedForUpdate(ctx, tx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range outboxes {
+			value, err := encodeMessage(item, config.Config.AppName, time.Now())
+			if err != nil {
+				logrus.Errorf("[OutboxRelay-RelayOnce] %s for order %s does not match its schema, parking it: %v", item.EventName, item.OrderUUID, err)
+				// payload tidak akan berubah, mengulang hanya menahan event lain di belakangnya
+				err = r.repository.GetOrderOutbox().MarkDead(ctx, tx, item.ID, err)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			err = r.producer.Publish(r.topic, item.OrderUUID.String(), value)
+			if err != nil && r.exhausted(item) {
+				logrus.Errorf("[OutboxRelay-RelayOnce] failed to publish %s for order %s after %d attempts, parking it: %v",
+					item.EventName, item.OrderUUID, item.Attempts+1, err)
+				err = r.repository.GetOrderOutbox().MarkDead(ctx, tx, item.ID, err)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				logrus.Errorf("[OutboxRelay-RelayOnce] failed to publish %s for order %s: %v", item.EventName, item.OrderUUID, err)
+				// berhenti di sini supaya urutan event per order tetap terjaga
+				return r.repository.GetOrderOutbox().MarkFailed(ctx, tx, item.ID, err)
+			}
+
+			err = r.repository.GetOrderOutbox().MarkPublished(ctx, tx, item.ID)
+			if err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if published > 0 {
+		logrus.Infof("[OutboxRelay-RelayOnce] published %d order events", published)
+	}
+
+	return published, nil
+}
+
+// exhausted melaporkan apakah percobaan publish saat ini adalah percobaan terakhir untuk event tersebut.
+func (r *Relay) exhausted(item models.OrderOutbox) bool {
+	return item.Attempts+1 >= r.maxAttempts
+}
+
+// encodeMessage membungkus payload outbox menjadi message Kafka dan memastikan hasilnya sesuai schema order v1.
+func encodeMessage(item models.OrderOutbox, sender string, sendingAt time.Time) ([]byte, error) {
+	message := dto.KafkaMessage[json.RawMessage]{
+		Event: dto.KafkaEvent{Name: item.EventName, Version: schemas.V1},
+		MetaData: dto.KafkaMetaData{
+			Sender:    sender,
+			SendingAt: sendingAt.Format(time.RFC3339),
+		},
+		Body: dto.KafkaBody[json.RawMessage]{
+			Type: dto.OrderDataType,
+			Data: json.RawMessage(item.Payload),
+		},
+	}
+
+	value, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schemas.Validate(schemas.Order, schemas.V1, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
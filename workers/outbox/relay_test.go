@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"encoding/json"
+	"order-service/constants"
+	"order-service/domain/dto"
+	"order-service/domain/models"
+	"order-service/schemas"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newOutbox(t *testing.T, status constants.OrderStatus, paidAt *time.Time) models.OrderOutbox {
+	t.Helper()
+
+	eventName, ok := status.GetEventName()
+	if !ok {
+		t.Fatalf("status %s has no event", status.GetStatusString())
+	}
+
+	orderUUID := uuid.New()
+	payload, err := json.Marshal(dto.OrderEventData{
+		UUID:      orderUUID,
+		Code:      "ORD-00001-20250101",
+		UserID:    uuid.New(),
+		PaymentID: uuid.New(),
+		Amount:    150000,
+		Status:    status.GetStatusString(),
+		IsPaid:    paidAt != nil,
+		OrderDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		PaidAt:    paidAt,
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+
+	return models.OrderOutbox{
+		ID:        1,
+		OrderUUID: orderUUID,
+		EventName: eventName.String(),
+		Payload:   string(payload),
+	}
+}
+
+func TestEncodeMessageMatchesOrderSchema(t *testing.T) {
+	paidAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		status constants.OrderStatus
+		paidAt *time.Time
+	}{
+		{status: constants.Pending},
+		{status: constants.PaymentSuccess, paidAt: &paidAt},
+		{status: constants.Expired},
+		{status: constants.Cancelled},
+		{status: constants.Failed},
+	} {
+		t.Run(string(tt.status.GetStatusString()), func(t *testing.T) {
+			value, err := encodeMessage(newOutbox(t, tt.status, tt.paidAt), "order-service", time.Now())
+			if err != nil {
+				t.Fatalf("encodeMessage: %v", err)
+			}
+
+			err = schemas.Validate(schemas.Order, schemas.V1, value)
+			if err != nil {
+				t.Fatalf("published message does not match order v1: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeMessageRejectsInvalidPayload(t *testing.T) {
+	item := newOutbox(t, constants.Pending, nil)
+	item.Payload = `{"uuid": "not-a-uuid", "code": ""}`
+
+	_, err := encodeMessage(item, "order-service", time.Now())
+	if err == nil {
+		t.Fatal("expected schema error for invalid payload")
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	relay := &Relay{maxAttempts: 3}
+
+	for _, tt := range []struct {
+		attempts  int
+		exhausted bool
+	}{
+		{attempts: 0},
+		{attempts: 1},
+		{attempts: 2, exhausted: true},
+		{attempts: 5, exhausted: true},
+	} {
+		item := models.OrderOutbox{Attempts: tt.attempts}
+		if got := relay.exhausted(item); got != tt.exhausted {
+			t.Errorf("exhausted with %d previous attempts = %v, want %v", tt.attempts, got, tt.exhausted)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package redrive
+
+import (
+	"context"
+	"order-service/services"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Filter struct {
+	OrderIDs []uuid.UUID
+	From     *time.Time
+	To       *time.Time
+}
+
+type Report struct {
+	Read      int
+	Skipped   int
+	Unchanged int
+	Changed   int
+	Failed    int
+}
+
+type Redriver struct {
+	service services.IServiceRegistry
+	filter  Filter
+	dryRun  bool
+}
+
+type IRedriver interface {
+	Run(context.Context, ISource) (*Report, error)
+}
+
+func NewRedriver(service services.IServiceRegistry, filter Filter, dryRun bool) IRedriver {
+	return &Redriver{
+		service: service,
+		filter:  filter,
+		dryRun:  dryRun,
+	}
+}
+
+// Run memproses ulang setiap message dari source lewat OrderService.HandlePayment.
+// Message yang tidak akan mengubah status order dilewati, dan dalam mode dry-run
+// hanya dilaporkan order mana yang akan berubah status.
+func (r *Redriver) Run(ctx context.Context, source ISource) (*Report, error) {
+	report := &Report{}
+
+	err := source.Read(ctx, func(message Message) error {
+		report.Read++
+		if message.Err != nil {
+			// message DLQ yang tidak sesuai schema tidak bisa diproses ulang, catat lalu lanjut
+			report.Failed++
+			logrus.Errorf("[Redrive] %s cannot be decoded: %v", message.Source, message.Err)
+			return nil
+		}
+
+		data := message.Content.Body.Data
+
+		if !r.match(message) {
+			report.Skipped++
+			return nil
+		}
+
+		plan, err := r.service.GetOrder().PlanPayment(ctx, &data)
+		if err != nil {
+			report.Failed++
+			logrus.Errorf("[Redrive] %s order %s: %v", message.Source, data.OrderID, err)
+			return nil
+		}
+
+		if !plan.WillChange {
+			report.Unchanged++
+			logrus.Infof("[Redrive] %s order %s unchanged: %s", message.Source, plan.OrderID, plan.Reason)
+			return nil
+		}
+
+		if r.dryRun {
+			report.Changed++
+			logrus.Infof("[Redrive] %s order %s would change %s -> %s", message.Source, plan.OrderID, plan.CurrentStatus, plan.TargetStatus)
+			return nil
+		}
+
+		err = r.service.GetOrder().HandlePayment(ctx, &data)
+		if err != nil {
+			report.Failed++
+			logrus.Errorf("[Redrive] %s order %s failed: %v", message.Source, data.OrderID, err)
+			return nil
+		}
+
+		report.Changed++
+		logrus.Infof("[Redrive] %s order %s changed %s -> %s", message.Source, plan.OrderID, plan.CurrentStatus, plan.TargetStatus)
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (r *Redriver) match(message Message) bool {
+	if len(r.filter.OrderIDs) > 0 {
+		found := false
+		for _, orderID := range r.filter.OrderIDs {
+			if orderID == message.Content.Body.Data.OrderID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.filter.From != nil && (message.Timestamp.IsZero() || message.Timestamp.Before(*r.filter.From)) {
+		return false
+	}
+
+	if r.filter.To != nil && (message.Timestamp.IsZero() || message.Timestamp.After(*r.filter.To)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,186 @@
+package redrive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/domain/dto"
+	"order-service/schemas"
+	"os"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Message adalah satu payment event yang akan diproses ulang beserta asalnya.
+// Err terisi jika message tidak bisa di-decode, source tetap lanjut ke message berikutnya.
+type Message struct {
+	Source    string
+	Content   dto.PaymentContent
+	Timestamp time.Time
+	Err       error
+}
+
+type ISource interface {
+	Read(ctx context.Context, fn func(Message) error) error
+}
+
+type fileSource struct {
+	path string
+}
+
+// NewFileSource membaca file JSON-lines berisi dto.PaymentContent, satu message per baris.
+func NewFileSource(path string) ISource {
+	return &fileSource{path: path}
+}
+
+func (f *fileSource) Read(ctx context.Context, fn func(Message) error) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		source := fmt.Sprintf("%s:%d", f.path, line)
+		content, err := schemas.DecodePayment(scanner.Bytes())
+		if err != nil {
+			err = fn(Message{Source: source, Err: err})
+		} else {
+			err = fn(Message{
+				Source:    source,
+				Content:   *content,
+				Timestamp: sendingAt(*content),
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+type topicSource struct {
+	brokers []string
+	topic   string
+}
+
+// NewTopicSource membaca semua message DLQ (dto.DeadLetterMessage) yang sudah ada di topic
+// saat command dijalankan, lalu berhenti.
+func NewTopicSource(brokers []string, topic string) ISource {
+	return &topicSource{brokers: brokers, topic: topic}
+}
+
+func (t *topicSource) Read(ctx context.Context, fn func(Message) error) error {
+	client, err := sarama.NewClient(t.brokers, sarama.NewConfig())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	partitions, err := client.Partitions(t.topic)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		err = t.readPartition(ctx, client, consumer, partition, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *topicSource) readPartition(ctx context.Context, client sarama.Client, consumer sarama.Consumer, partition int32, fn func(Message) error) error {
+	oldest, err := client.GetOffset(t.topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return err
+	}
+
+	newest, err := client.GetOffset(t.topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return err
+	}
+
+	if oldest >= newest {
+		return nil
+	}
+
+	partitionConsumer, err := consumer.ConsumePartition(t.topic, partition, oldest)
+	if err != nil {
+		return err
+	}
+	defer partitionConsumer.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case consumerErr := <-partitionConsumer.Errors():
+			return consumerErr
+		case message := <-partitionConsumer.Messages():
+			err = t.handle(message, fn)
+			if err != nil {
+				return err
+			}
+
+			if message.Offset >= newest-1 {
+				return nil
+			}
+		}
+	}
+}
+
+func (t *topicSource) handle(message *sarama.ConsumerMessage, fn func(Message) error) error {
+	source := fmt.Sprintf("%s/%d@%d", t.topic, message.Partition, message.Offset)
+
+	var deadLetter dto.DeadLetterMessage
+	err := json.Unmarshal(message.Value, &deadLetter)
+	if err != nil {
+		return fn(Message{Source: source, Err: err})
+	}
+
+	content, err := schemas.DecodePayment(deadLetter.Payload)
+	if err != nil {
+		return fn(Message{Source: source, Err: err})
+	}
+
+	timestamp := sendingAt(*content)
+	if timestamp.IsZero() {
+		timestamp = deadLetter.FailedAt
+	}
+
+	return fn(Message{
+		Source:    source,
+		Content:   *content,
+		Timestamp: timestamp,
+	})
+}
+
+func sendingAt(content dto.PaymentContent) time.Time {
+	timestamp, err := time.Parse(time.RFC3339, content.Metadata.SendingAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return timestamp
+}